@@ -3,6 +3,7 @@ package main
 import (
 	"Praiseson6065/ocrolus-be/handlers"
 	"Praiseson6065/ocrolus-be/middleware"
+	"Praiseson6065/ocrolus-be/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -25,11 +26,59 @@ func ApiRouter(r *gin.Engine) {
 	{
 		userRoutes.GET("/", userHandler.GetUser)
 		userRoutes.PUT("/", userHandler.UpdateUser)
+		userRoutes.PATCH("/", userHandler.PatchUser)
 		userRoutes.DELETE("/:id", userHandler.DeleteUser)
 	}
+
+	// Reading list routes
+	readingListHandler := &handlers.ReadingListHandler{}
+	readingListRoutes := userRoutes.Group("/reading-lists")
+	{
+		readingListRoutes.GET("", readingListHandler.ListReadingLists)
+		readingListRoutes.POST("", readingListHandler.CreateReadingList)
+		readingListRoutes.GET("/:listId", readingListHandler.GetReadingList)
+		readingListRoutes.PUT("/:listId", readingListHandler.UpdateReadingList)
+		readingListRoutes.DELETE("/:listId", readingListHandler.DeleteReadingList)
+		readingListRoutes.PUT("/:listId/order", readingListHandler.ReorderItems)
+		readingListRoutes.POST("/:listId/items", readingListHandler.AddItem)
+		readingListRoutes.PUT("/:listId/items/:itemId", readingListHandler.UpdateItem)
+		readingListRoutes.DELETE("/:listId/items/:itemId", readingListHandler.RemoveItem)
+	}
+
+	// Public lists are shared by link
+	sharedListRoutes := apiRoutes.Group("/reading-lists", middleware.OptionalAuthenticator())
+	{
+		sharedListRoutes.GET("/:listId", readingListHandler.GetSharedReadingList)
+	}
+
+	// Series routes
+	seriesHandler := &handlers.SeriesHandler{}
+	userRoutes.GET("/series", seriesHandler.ListMySeries)
+
+	seriesRoutes := apiRoutes.Group("/series", middleware.OptionalAuthenticator())
+	{
+		seriesRoutes.GET("/:id", seriesHandler.GetSeries)
+	}
+
+	authSeriesRoutes := apiRoutes.Group("/series", middleware.Authenicator())
+	{
+		authSeriesRoutes.POST("", seriesHandler.CreateSeries)
+		authSeriesRoutes.PUT("/:id", seriesHandler.UpdateSeries)
+		authSeriesRoutes.DELETE("/:id", seriesHandler.DeleteSeries)
+		authSeriesRoutes.POST("/:id/parts", seriesHandler.AddPart)
+		authSeriesRoutes.DELETE("/:id/parts/:articleId", seriesHandler.RemovePart)
+		authSeriesRoutes.PUT("/:id/order", seriesHandler.ReorderParts)
+	}
 	
 	// Article routes
 	articleHandler := &handlers.ArticleHandler{}
+	commentHandler := &handlers.CommentHandler{}
+	reactionHandler := &handlers.ReactionHandler{}
+	collaboratorHandler := &handlers.CollaboratorHandler{}
+	suggestionHandler := &handlers.SuggestionHandler{}
+	editLockHandler := &handlers.EditLockHandler{}
+	previewLinkHandler := &handlers.PreviewLinkHandler{}
+	moderationHandler := &handlers.ModerationHandler{}
 
 	// Public article routes (no authentication required)
 	articleRoutes := apiRoutes.Group("/articles", middleware.OptionalAuthenticator())
@@ -37,6 +86,14 @@ func ApiRouter(r *gin.Engine) {
 		// Public endpoints for articles (read-only)
 		articleRoutes.GET("", articleHandler.ListArticles)
 		articleRoutes.GET("/:id", articleHandler.GetArticle)
+		articleRoutes.GET("/by-slug/:slug", articleHandler.GetArticleBySlug)
+
+		// Password-protected articles are unlocked with an access grant
+		articleRoutes.POST("/:id/access", articleHandler.GrantArticleAccess)
+
+		// Comments are readable by everyone
+		articleRoutes.GET("/:id/comments", commentHandler.ListComments)
+		articleRoutes.GET("/:id/comments/:commentId/replies", commentHandler.ListReplies)
 	}
 
 	// Protected article routes (authentication required)
@@ -45,9 +102,90 @@ func ApiRouter(r *gin.Engine) {
 		// Create, update, delete (require authentication)
 		authArticleRoutes.POST("", articleHandler.CreateArticle)
 		authArticleRoutes.PUT("/:id", articleHandler.UpdateArticle)
+		authArticleRoutes.PATCH("/:id", articleHandler.PatchArticle)
 		authArticleRoutes.DELETE("/:id", articleHandler.DeleteArticle)
 
+		// Keyword-based tag suggestions for drafts
+		authArticleRoutes.POST("/suggest-tags", articleHandler.SuggestTags)
+
+		// Editorial workflow
+		authArticleRoutes.POST("/:id/transition", articleHandler.TransitionArticle)
+		authArticleRoutes.PUT("/:id/reviewer", articleHandler.AssignReviewer)
+		authArticleRoutes.GET("/:id/transitions", articleHandler.ListTransitions)
+
+		// Collaborators
+		authArticleRoutes.GET("/:id/collaborators", collaboratorHandler.ListCollaborators)
+		authArticleRoutes.POST("/:id/collaborators", collaboratorHandler.AddCollaborator)
+		authArticleRoutes.PUT("/:id/collaborators/:userId", collaboratorHandler.UpdateCollaborator)
+		authArticleRoutes.DELETE("/:id/collaborators/:userId", collaboratorHandler.RemoveCollaborator)
+		authArticleRoutes.POST("/:id/transfer", collaboratorHandler.TransferOwnership)
+
+		// Edit locks
+		authArticleRoutes.POST("/:id/lock", editLockHandler.AcquireLock)
+		authArticleRoutes.PUT("/:id/lock", editLockHandler.RenewLock)
+		authArticleRoutes.DELETE("/:id/lock", editLockHandler.ReleaseLock)
+
+		// Draft preview links
+		authArticleRoutes.POST("/:id/preview-links", previewLinkHandler.CreatePreviewLink)
+		authArticleRoutes.GET("/:id/preview-links", previewLinkHandler.ListPreviewLinks)
+		authArticleRoutes.DELETE("/:id/preview-links/:linkId", previewLinkHandler.RevokePreviewLink)
+
+		// Reporting abusive content
+		authArticleRoutes.POST("/:id/reports", moderationHandler.ReportArticle)
+
+		// Near-duplicate content, for editors and moderators
+		authArticleRoutes.GET("/:id/similar-content", articleHandler.GetSimilarContent)
+
+		// Suggested edits
+		authArticleRoutes.POST("/:id/suggestions", suggestionHandler.CreateSuggestion)
+		authArticleRoutes.GET("/:id/suggestions", suggestionHandler.ListSuggestions)
+		authArticleRoutes.GET("/:id/suggestions/:suggestionId", suggestionHandler.GetSuggestion)
+		authArticleRoutes.POST("/:id/suggestions/:suggestionId/accept", suggestionHandler.AcceptSuggestion)
+		authArticleRoutes.POST("/:id/suggestions/:suggestionId/reject", suggestionHandler.RejectSuggestion)
+		authArticleRoutes.DELETE("/:id/suggestions/:suggestionId", suggestionHandler.WithdrawSuggestion)
+
+		// Comments
+		authArticleRoutes.POST("/:id/comments", commentHandler.CreateComment)
+		authArticleRoutes.PUT("/:id/comments/settings", commentHandler.UpdateCommentSettings)
+		authArticleRoutes.PUT("/:id/comments/:commentId", commentHandler.UpdateComment)
+		authArticleRoutes.DELETE("/:id/comments/:commentId", commentHandler.DeleteComment)
+
+		// Reactions
+		authArticleRoutes.PUT("/:id/reactions/:type", reactionHandler.AddReaction)
+		authArticleRoutes.DELETE("/:id/reactions/:type", reactionHandler.RemoveReaction)
+
+		// Bookmark shortcut for the default reading list
+		authArticleRoutes.PUT("/:id/bookmark", readingListHandler.AddBookmark)
+		authArticleRoutes.DELETE("/:id/bookmark", readingListHandler.RemoveBookmark)
+
 		// User's recently viewed articles
 		authArticleRoutes.GET("/recently-viewed", articleHandler.GetRecentlyViewedArticles)
+
+		// Trash bin
+		authArticleRoutes.GET("/trash", articleHandler.ListTrash)
+		authArticleRoutes.POST("/:id/restore", articleHandler.RestoreArticle)
+		authArticleRoutes.DELETE("/:id/purge", articleHandler.PurgeArticle)
+	}
+
+	// Draft previews are opened by token, without an account
+	apiRoutes.GET("/preview/:token", previewLinkHandler.GetPreview)
+
+	// Moderation queue
+	moderationRoutes := apiRoutes.Group("/moderation", middleware.Authenicator(), middleware.RequireRole(models.RoleModerator, models.RoleAdmin))
+	{
+		moderationRoutes.GET("/reports", moderationHandler.ListReports)
+		moderationRoutes.GET("/reports/:reportId", moderationHandler.GetReport)
+		moderationRoutes.PUT("/reports/:reportId/assignee", moderationHandler.AssignReport)
+		moderationRoutes.POST("/reports/:reportId/resolve", moderationHandler.ResolveReport)
+		moderationRoutes.POST("/users/:userId/unsuspend", moderationHandler.LiftSuspension)
+		moderationRoutes.GET("/actions", moderationHandler.ListActions)
+		moderationRoutes.GET("/screenings", moderationHandler.ListScreenings)
+		moderationRoutes.POST("/screenings/:screeningId/review", moderationHandler.ReviewScreening)
+	}
+
+	// Admin routes
+	adminRoutes := apiRoutes.Group("/admin", middleware.Authenicator(), middleware.RequireRole(models.RoleAdmin))
+	{
+		adminRoutes.DELETE("/articles/:id/lock", editLockHandler.ForceReleaseLock)
 	}
 }
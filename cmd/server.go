@@ -2,9 +2,13 @@ package main
 
 import (
 	"Praiseson6065/ocrolus-be/config"
+	"Praiseson6065/ocrolus-be/database"
 	"Praiseson6065/ocrolus-be/middleware"
+	"Praiseson6065/ocrolus-be/scheduler"
+	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,6 +17,14 @@ func Server() error {
 	// Get port from config
 	port := config.Config.Server.Port
 
+	// Start background jobs
+	interval := time.Duration(config.Config.Scheduler.Interval) * time.Second
+	go scheduler.Start(context.Background(), interval,
+		scheduler.Job{Name: "article-schedule", Run: database.ApplyArticleSchedules},
+		scheduler.Job{Name: "edit-lock-cleanup", Run: database.PurgeExpiredEditLocks},
+		scheduler.Job{Name: "trash-purge", Run: database.PurgeExpiredTrash},
+	)
+
 	r := gin.New()
 	r.Use(middleware.CORS())
 	r.Use(gin.Logger())
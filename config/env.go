@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 
 	"github.com/joho/godotenv"
 )
@@ -14,6 +15,15 @@ type Configuration struct {
 	Server      ServerConfig
 	Database    DatabaseConfig
 	JWT         JWTConfig
+	Scheduler   SchedulerConfig
+	Comments    CommentsConfig
+	Concurrency ConcurrencyConfig
+	EditLocks   EditLocksConfig
+	Summaries   SummariesConfig
+	Trash       TrashConfig
+	Visibility  VisibilityConfig
+	Moderation  ModerationConfig
+	Screening   ScreeningConfig
 }
 
 type ServerConfig struct {
@@ -33,6 +43,74 @@ type JWTConfig struct {
 	Expire int
 }
 
+type SchedulerConfig struct {
+	// Interval is the number of seconds between scheduler runs
+	Interval int
+}
+
+type CommentsConfig struct {
+	// EditWindow is the number of minutes a comment can be edited after posting
+	EditWindow int
+}
+
+type ConcurrencyConfig struct {
+	// RequireIfMatch rejects updates and deletes that do not send If-Match
+	RequireIfMatch bool
+}
+
+type EditLocksConfig struct {
+	// Lease is the number of seconds an edit lock lasts without a heartbeat
+	Lease int
+}
+
+type SummariesConfig struct {
+	// Sentences is the length of generated article summaries; 0 turns them off
+	Sentences int
+}
+
+type VisibilityConfig struct {
+	// AccessGrantTTL is the number of minutes a password-protected article
+	// stays readable after the password was entered
+	AccessGrantTTL int
+}
+
+type ModerationConfig struct {
+	// HideThreshold is the number of people reporting an article before it
+	// is hidden pending review; 0 never hides articles automatically
+	HideThreshold int
+}
+
+type ScreeningConfig struct {
+	// Enabled runs article submissions through content screening
+	Enabled bool
+	// Blocklist holds extra comma-separated blocked terms, and BlocklistFile
+	// the path of a file with one term per line
+	Blocklist     string
+	BlocklistFile string
+	// BlocklistReject is the number of distinct blocked terms that rejects
+	// a submission outright; 0 only holds them
+	BlocklistReject int
+	// MaxLinks is the number of links per hundred words above which a
+	// submission is held; 0 disables the check
+	MaxLinks int
+	// MaxRepetition is the percentage of repeated phrases above which a
+	// submission is held; 0 disables the check
+	MaxRepetition int
+	// SpamHold and SpamReject are the spam probabilities, in percent, at
+	// which the classifier holds or rejects a submission; 0 disables them
+	SpamHold   int
+	SpamReject int
+	// MaxSimilarity is the percentage of content shared with another
+	// author's article above which a submission is held; 0 disables it
+	MaxSimilarity int
+}
+
+type TrashConfig struct {
+	// Retention is the number of days deleted articles are kept before they
+	// are purged; 0 keeps them forever
+	Retention int
+}
+
 var Config Configuration
 
 func ConfigLoad() {
@@ -60,6 +138,41 @@ func ConfigLoad() {
 			Secret: getEnv("JWT_SECRET", "ocrolus-secret-key"),
 			Expire: getEnvAsInt("JWT_EXPIRE", 24),
 		},
+		Scheduler: SchedulerConfig{
+			Interval: getEnvAsInt("SCHEDULER_INTERVAL", 30),
+		},
+		Comments: CommentsConfig{
+			EditWindow: getEnvAsInt("COMMENT_EDIT_WINDOW", 15),
+		},
+		Concurrency: ConcurrencyConfig{
+			RequireIfMatch: getEnvAsBool("REQUIRE_IF_MATCH", false),
+		},
+		EditLocks: EditLocksConfig{
+			Lease: getEnvAsPositiveInt("EDIT_LOCK_LEASE", 120),
+		},
+		Summaries: SummariesConfig{
+			Sentences: getEnvAsInt("SUMMARY_SENTENCES", 3),
+		},
+		Trash: TrashConfig{
+			Retention: getEnvAsInt("TRASH_RETENTION", 30),
+		},
+		Visibility: VisibilityConfig{
+			AccessGrantTTL: getEnvAsInt("ARTICLE_ACCESS_TTL", 30),
+		},
+		Moderation: ModerationConfig{
+			HideThreshold: getEnvAsInt("REPORT_HIDE_THRESHOLD", 5),
+		},
+		Screening: ScreeningConfig{
+			Enabled:         getEnvAsBool("SCREENING_ENABLED", true),
+			Blocklist:       getEnv("SCREENING_BLOCKLIST", ""),
+			BlocklistFile:   getEnv("SCREENING_BLOCKLIST_FILE", ""),
+			BlocklistReject: getEnvAsInt("SCREENING_BLOCKLIST_REJECT", 3),
+			MaxLinks:        getEnvAsInt("SCREENING_MAX_LINKS", 5),
+			MaxRepetition:   getEnvAsInt("SCREENING_MAX_REPETITION", 50),
+			SpamHold:        getEnvAsInt("SCREENING_SPAM_HOLD", 90),
+			SpamReject:      getEnvAsInt("SCREENING_SPAM_REJECT", 99),
+			MaxSimilarity:   getEnvAsInt("SCREENING_MAX_SIMILARITY", 0),
+		},
 	}
 
 	// Log loaded configuration for debugging
@@ -92,6 +205,33 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return value
 }
 
+// getEnvAsPositiveInt gets an environment variable as a positive integer or
+// returns a default value
+func getEnvAsPositiveInt(key string, defaultValue int) int {
+	value := getEnvAsInt(key, defaultValue)
+	if value <= 0 {
+		log.Printf("Warning: Invalid value for %s, using default: must be positive", key)
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvAsBool gets an environment variable as a boolean or returns a default value
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		log.Printf("Warning: Invalid value for %s, using default: %v", key, err)
+		return defaultValue
+	}
+
+	return value
+}
+
 // logConfigValues logs the loaded configuration for debugging
 func logConfigValues() {
 	log.Printf("Environment: %s", Config.Environment)
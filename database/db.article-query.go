@@ -0,0 +1,276 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"Praiseson6065/ocrolus-be/models"
+	"Praiseson6065/ocrolus-be/util"
+
+	"gorm.io/gorm"
+)
+
+// Limits on article list queries, to keep them cheap to plan and run
+const (
+	maxArticleFilters    = 10
+	maxArticleFilterIn   = 20
+	maxArticleSortKeys   = 3
+	maxTitleFilterLength = 100
+)
+
+// QueryError describes a filter or sort expression that could not be parsed.
+// Its message is safe to return to the client.
+type QueryError struct {
+	Param   string
+	Message string
+}
+
+func (e *QueryError) Error() string {
+	return "invalid " + e.Param + ": " + e.Message
+}
+
+// ArticleFilter is one parsed condition of an article list query
+type ArticleFilter struct {
+	Field    string
+	Operator string
+	Values   []string
+	at       time.Time // parsed value of a date filter
+}
+
+// ArticleSort is one key of an article list ordering
+type ArticleSort struct {
+	Field      string
+	Descending bool
+}
+
+// Filter operators, longest first so that ">=" is not read as ">"
+var articleFilterOperators = []string{">=", "<=", ":", "~", ">", "<"}
+
+// articleFilterFields lists the fields that can be filtered on and the
+// operators each of them accepts
+var articleFilterFields = map[string][]string{
+	"author":  {":"},
+	"created": {":", ">", ">=", "<", "<="},
+	"updated": {":", ">", ">=", "<", "<="},
+	"title":   {"~"},
+	"state":   {":"},
+	"tag":     {":"},
+}
+
+// articleSortColumns maps sort keys to the columns they order by
+var articleSortColumns = map[string]string{
+	"created": "created_at",
+	"updated": "updated_at",
+	"title":   "title",
+	"state":   "state",
+}
+
+// ParseArticleFilters parses filter expressions of the form
+// <field><operator><value>, for example "created>=2024-01-01",
+// "title~release notes" or "state:draft|in_review". A colon means equality,
+// with alternatives separated by "|"; "~" means the title contains the
+// value. Dates may be given as RFC 3339 times or as plain days, in which
+// case the whole day is matched.
+func ParseArticleFilters(expressions []string) ([]ArticleFilter, error) {
+	if len(expressions) > maxArticleFilters {
+		return nil, &QueryError{"filter", fmt.Sprintf("at most %d filters are allowed", maxArticleFilters)}
+	}
+
+	filters := make([]ArticleFilter, 0, len(expressions))
+	for _, expression := range expressions {
+		filter, err := parseArticleFilter(expression)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+func parseArticleFilter(expression string) (ArticleFilter, error) {
+	var filter ArticleFilter
+
+	index := -1
+	for i := range expression {
+		for _, operator := range articleFilterOperators {
+			if strings.HasPrefix(expression[i:], operator) {
+				index, filter.Operator = i, operator
+				break
+			}
+		}
+		if index >= 0 {
+			break
+		}
+	}
+	if index < 0 {
+		return filter, &QueryError{"filter", fmt.Sprintf("%q has no operator", expression)}
+	}
+
+	filter.Field = strings.TrimSpace(expression[:index])
+	value := strings.TrimSpace(expression[index+len(filter.Operator):])
+
+	operators, ok := articleFilterFields[filter.Field]
+	if !ok {
+		return filter, &QueryError{"filter", fmt.Sprintf("unknown field %q", filter.Field)}
+	}
+	if !containsString(operators, filter.Operator) {
+		return filter, &QueryError{"filter", fmt.Sprintf("operator %q is not supported for %s", filter.Operator, filter.Field)}
+	}
+	if value == "" {
+		return filter, &QueryError{"filter", fmt.Sprintf("%s needs a value", filter.Field)}
+	}
+
+	switch filter.Field {
+	case "title":
+		if len(value) > maxTitleFilterLength {
+			return filter, &QueryError{"filter", fmt.Sprintf("title filter is longer than %d characters", maxTitleFilterLength)}
+		}
+		filter.Values = []string{value}
+
+	case "created", "updated":
+		t, day, err := parseFilterTime(value)
+		if err != nil {
+			return filter, &QueryError{"filter", fmt.Sprintf("%q is not a date or RFC 3339 time", value)}
+		}
+		filter.Values = []string{value}
+		filter.at = t
+		// A plain day covers all of that day
+		if day {
+			switch filter.Operator {
+			case ":":
+				filter.Operator = "day"
+			case ">":
+				filter.at = t.AddDate(0, 0, 1)
+				filter.Operator = ">="
+			case "<=":
+				filter.at = t.AddDate(0, 0, 1)
+				filter.Operator = "<"
+			}
+		}
+
+	default:
+		values := strings.Split(value, "|")
+		if len(values) > maxArticleFilterIn {
+			return filter, &QueryError{"filter", fmt.Sprintf("%s accepts at most %d values", filter.Field, maxArticleFilterIn)}
+		}
+		for _, v := range values {
+			v = strings.TrimSpace(v)
+			if filter.Field == "tag" {
+				v = util.Slugify(v)
+			}
+			if v == "" {
+				return filter, &QueryError{"filter", fmt.Sprintf("%s has an empty value", filter.Field)}
+			}
+			if filter.Field == "state" && !models.IsValidArticleState(v) {
+				return filter, &QueryError{"filter", fmt.Sprintf("unknown state %q", v)}
+			}
+			filter.Values = append(filter.Values, v)
+		}
+	}
+
+	return filter, nil
+}
+
+// parseFilterTime accepts an RFC 3339 time or a YYYY-MM-DD day, reporting
+// whether a day was given
+func parseFilterTime(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t, false, err
+}
+
+// ParseArticleSort parses a comma-separated list of sort keys, each
+// optionally prefixed with "-" for descending order, e.g. "-updated,title"
+func ParseArticleSort(expression string) ([]ArticleSort, error) {
+	if expression == "" {
+		return nil, nil
+	}
+
+	keys := strings.Split(expression, ",")
+	if len(keys) > maxArticleSortKeys {
+		return nil, &QueryError{"sort", fmt.Sprintf("at most %d sort keys are allowed", maxArticleSortKeys)}
+	}
+
+	sorts := make([]ArticleSort, 0, len(keys))
+	seen := map[string]bool{}
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		sort := ArticleSort{Field: strings.TrimPrefix(key, "-"), Descending: strings.HasPrefix(key, "-")}
+		if _, ok := articleSortColumns[sort.Field]; !ok {
+			return nil, &QueryError{"sort", fmt.Sprintf("unknown sort key %q", sort.Field)}
+		}
+		if seen[sort.Field] {
+			return nil, &QueryError{"sort", fmt.Sprintf("%s is sorted on more than once", sort.Field)}
+		}
+		seen[sort.Field] = true
+		sorts = append(sorts, sort)
+	}
+	return sorts, nil
+}
+
+// articleFilterScope applies parsed filters to an article query. Field names
+// and operators come from the whitelists above; values are always bound as
+// parameters.
+func articleFilterScope(filters []ArticleFilter) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		for _, filter := range filters {
+			switch filter.Field {
+			case "author":
+				tx = tx.Where("articles.author_id IN ?", filter.Values)
+			case "state":
+				tx = tx.Where("articles.state IN ?", filter.Values)
+			case "title":
+				tx = tx.Where(`articles.title ILIKE ? ESCAPE '\'`, "%"+escapeLike(filter.Values[0])+"%")
+			case "tag":
+				tx = tx.Where("articles.id IN (?)", db.Table("article_tags").
+					Select("article_tags.article_id").
+					Joins("JOIN tags ON tags.id = article_tags.tag_id").
+					Where("tags.slug IN ?", filter.Values))
+			case "created", "updated":
+				column := "articles." + articleSortColumns[filter.Field]
+				if filter.Operator == "day" {
+					tx = tx.Where(column+" >= ? AND "+column+" < ?", filter.at, filter.at.AddDate(0, 0, 1))
+				} else if filter.Operator == ":" {
+					tx = tx.Where(column+" = ?", filter.at)
+				} else {
+					tx = tx.Where(column+" "+filter.Operator+" ?", filter.at)
+				}
+			}
+		}
+		return tx
+	}
+}
+
+// articleOrder builds the ORDER BY clause for the given sort keys, falling
+// back to newest first. The id always breaks ties so pages are stable.
+func articleOrder(sorts []ArticleSort) string {
+	if len(sorts) == 0 {
+		return "created_at DESC, id DESC"
+	}
+	parts := make([]string, 0, len(sorts)+1)
+	for _, sort := range sorts {
+		direction := " ASC"
+		if sort.Descending {
+			direction = " DESC"
+		}
+		parts = append(parts, "articles."+articleSortColumns[sort.Field]+direction)
+	}
+	return strings.Join(append(parts, "articles.id DESC"), ", ")
+}
+
+// escapeLike escapes the LIKE wildcards in s so it is matched literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
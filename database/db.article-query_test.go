@@ -0,0 +1,108 @@
+package database
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseArticleFilters(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		expression string
+		want       ArticleFilter
+	}{
+		{"state:draft|in_review", ArticleFilter{Field: "state", Operator: ":", Values: []string{"draft", "in_review"}}},
+		{"tag: Go Lang ", ArticleFilter{Field: "tag", Operator: ":", Values: []string{"go-lang"}}},
+		{"title~release notes", ArticleFilter{Field: "title", Operator: "~", Values: []string{"release notes"}}},
+		{"author:u1", ArticleFilter{Field: "author", Operator: ":", Values: []string{"u1"}}},
+		{"created>=2024-03-01", ArticleFilter{Field: "created", Operator: ">=", Values: []string{"2024-03-01"}, at: day}},
+		// Plain days cover the whole day
+		{"created:2024-03-01", ArticleFilter{Field: "created", Operator: "day", Values: []string{"2024-03-01"}, at: day}},
+		{"created>2024-03-01", ArticleFilter{Field: "created", Operator: ">=", Values: []string{"2024-03-01"}, at: day.AddDate(0, 0, 1)}},
+		{"updated<=2024-03-01", ArticleFilter{Field: "updated", Operator: "<", Values: []string{"2024-03-01"}, at: day.AddDate(0, 0, 1)}},
+		{"updated<2024-03-01T10:00:00Z", ArticleFilter{Field: "updated", Operator: "<", Values: []string{"2024-03-01T10:00:00Z"}, at: day.Add(10 * time.Hour)}},
+	}
+	for _, tt := range tests {
+		filters, err := ParseArticleFilters([]string{tt.expression})
+		if err != nil {
+			t.Errorf("ParseArticleFilters(%q): unexpected error: %v", tt.expression, err)
+			continue
+		}
+		if len(filters) != 1 || !reflect.DeepEqual(filters[0], tt.want) {
+			t.Errorf("ParseArticleFilters(%q) = %+v, want %+v", tt.expression, filters, tt.want)
+		}
+	}
+}
+
+func TestParseArticleFiltersInvalid(t *testing.T) {
+	tests := []string{
+		"state",
+		"",
+		"color:red",
+		"title:hello",
+		"state>draft",
+		"state:",
+		"state:draft||published",
+		"state:gone",
+		"tag:!!!",
+		"created:yesterday",
+		"created>=2024-13-01",
+		"title~" + strings.Repeat("a", maxTitleFilterLength+1),
+		"tag:" + strings.Repeat("a|", maxArticleFilterIn) + "a",
+	}
+	for _, expression := range tests {
+		_, err := ParseArticleFilters([]string{expression})
+		if _, ok := err.(*QueryError); !ok {
+			t.Errorf("ParseArticleFilters(%q): got %v, want a QueryError", expression, err)
+		}
+	}
+
+	tooMany := make([]string, maxArticleFilters+1)
+	for i := range tooMany {
+		tooMany[i] = "state:draft"
+	}
+	if _, err := ParseArticleFilters(tooMany); err == nil {
+		t.Errorf("ParseArticleFilters accepted %d filters", len(tooMany))
+	}
+}
+
+func TestParseArticleSort(t *testing.T) {
+	sorts, err := ParseArticleSort("-updated, title")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []ArticleSort{{Field: "updated", Descending: true}, {Field: "title"}}
+	if !reflect.DeepEqual(sorts, want) {
+		t.Errorf("ParseArticleSort = %+v, want %+v", sorts, want)
+	}
+
+	if sorts, err := ParseArticleSort(""); err != nil || sorts != nil {
+		t.Errorf("ParseArticleSort(\"\") = %+v, %v; want no sort", sorts, err)
+	}
+
+	invalid := []string{
+		"popularity",
+		"-",
+		"title,",
+		"title,-title",
+		"--title",
+		"created,updated,title,state",
+	}
+	for _, expression := range invalid {
+		_, err := ParseArticleSort(expression)
+		if _, ok := err.(*QueryError); !ok {
+			t.Errorf("ParseArticleSort(%q): got %v, want a QueryError", expression, err)
+		}
+	}
+}
+
+func TestArticleOrder(t *testing.T) {
+	// The ID breaks ties so that pages do not overlap
+	got := articleOrder([]ArticleSort{{Field: "title"}, {Field: "created", Descending: true}})
+	want := "articles.title ASC, articles.created_at DESC, articles.id DESC"
+	if got != want {
+		t.Errorf("articleOrder = %q, want %q", got, want)
+	}
+}
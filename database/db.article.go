@@ -3,15 +3,71 @@ package database
 import (
 	"Praiseson6065/ocrolus-be/models"
 	"errors"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
+// ErrVersionConflict is returned when a row was changed by someone else since
+// it was read
+var ErrVersionConflict = errors.New("resource has been modified")
+
+// nextVersion increments a row's version counter. Every update to an article
+// or user includes it so that ETags change with the content.
+var nextVersion = gorm.Expr("version + 1")
+
+// preloadArticle loads the relations shown with every article
+func preloadArticle(tx *gorm.DB) *gorm.DB {
+	return tx.Preload("Author").Preload("Reviewer").Preload("Collaborators.User").Preload("Tags")
+}
+
+// CreateArticle stores a new article. article.Slug may hold an explicitly
+// requested slug; otherwise one is generated from the title. Only the names
+// of article.Tags are used; existing tags are reused by slug. The article
+// goes through content screening first, and a rejection is returned as a
+// ScreeningRejectedError.
 func CreateArticle(ctx *gin.Context, article *models.Article) (string, error) {
-	tx := db.WithContext(ctx).Create(article)
-	if tx.Error != nil {
-		return "", tx.Error
+	renderArticleContent(article)
+	tagNames := articleTagNames(article)
+	article.Tags = nil
+
+	// Rejected articles are not saved; held ones are saved hidden until a
+	// moderator has reviewed them
+	screened := screenArticle(ctx, article)
+	if screened != nil && screened.Verdict == models.ScreeningReject {
+		return "", rejectScreenedArticle(ctx, screened)
+	}
+	if screened != nil && screened.Verdict == models.ScreeningHold {
+		now := db.NowFunc()
+		article.HeldAt = &now
+	}
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := saveWithSlug(tx, article, article.Slug, func(tx *gorm.DB) error {
+			return tx.Create(article).Error
+		}); err != nil {
+			return err
+		}
+		if err := setArticleTags(tx, article, tagNames); err != nil {
+			return err
+		}
+		if screened != nil {
+			screened.ArticleID = &article.ID
+			if err := tx.Create(screened).Error; err != nil {
+				return err
+			}
+		}
+		if err := saveArticleSignature(tx, article); err != nil {
+			return err
+		}
+		if err := saveArticleKeywords(tx, article); err != nil {
+			return err
+		}
+		return recordSlug(tx, article)
+	})
+	if err != nil {
+		return "", err
 	}
 
 	return article.ID, nil
@@ -19,7 +75,7 @@ func CreateArticle(ctx *gin.Context, article *models.Article) (string, error) {
 
 func GetArticleByID(ctx *gin.Context, id string) (*models.Article, error) {
 	var article models.Article
-	result := db.WithContext(ctx).Preload("Author").Where("id = ?", id).First(&article)
+	result := db.WithContext(ctx).Scopes(preloadArticle).Where("id = ?", id).First(&article)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, errors.New("article not found")
@@ -29,44 +85,73 @@ func GetArticleByID(ctx *gin.Context, id string) (*models.Article, error) {
 	return &article, nil
 }
 
-func ListArticles(ctx *gin.Context, page, pageSize int, authorID string) ([]models.Article, int64, error) {
-	var articles []models.Article
-	var count int64
+// ArticleListOptions narrows the articles returned by ListArticles
+type ArticleListOptions struct {
+	AuthorID   string
+	MemberID   string // author or any collaborator
+	ReviewerID string
+	States     []string
+	Published  bool // only articles that are live now
+	Visibility []string
+	Filters    []ArticleFilter
+	Sort       []ArticleSort // newest first when empty
+}
+
+// filteredArticles builds the query for the articles matching opts
+func filteredArticles(ctx *gin.Context, opts ArticleListOptions) *gorm.DB {
 	query := db.WithContext(ctx).Model(&models.Article{})
 
 	// Filter by author if specified
-	if authorID != "" {
-		query = query.Where("author_id = ?", authorID)
+	if opts.AuthorID != "" {
+		query = query.Where("author_id = ?", opts.AuthorID)
 	}
 
-	// Count total articles matching the filter
-	if err := query.Count(&count).Error; err != nil {
-		return nil, 0, err
+	// Filter by authorship or collaboration if specified
+	if opts.MemberID != "" {
+		query = query.Where("author_id = ? OR id IN (?)", opts.MemberID,
+			db.Model(&models.ArticleCollaborator{}).Select("article_id").Where("user_id = ?", opts.MemberID))
 	}
 
-	// Apply pagination and fetch articles with author information
-	offset := (page - 1) * pageSize
-	result := query.Preload("Author").Offset(offset).Limit(pageSize).Order("created_at DESC").Find(&articles)
-	if result.Error != nil {
-		return nil, 0, result.Error
+	// Filter by assigned reviewer if specified
+	if opts.ReviewerID != "" {
+		query = query.Where("reviewer_id = ?", opts.ReviewerID)
 	}
 
-	return articles, count, nil
+	// Filter by editorial state if specified
+	if len(opts.States) > 0 {
+		query = query.Where("state IN ?", opts.States)
+	}
+
+	if opts.Published {
+		query = query.Scopes(publishedWindow(db.NowFunc()))
+	}
+
+	// Filter by visibility level if specified
+	if len(opts.Visibility) > 0 {
+		query = query.Where("visibility IN ?", opts.Visibility)
+	}
+
+	if len(opts.Filters) > 0 {
+		query = query.Scopes(articleFilterScope(opts.Filters))
+	}
+
+	return query
 }
 
-func ListPublishedArticles(ctx *gin.Context, page, pageSize int) ([]models.Article, int64, error) {
+func ListArticles(ctx *gin.Context, page, pageSize int, opts ArticleListOptions) ([]models.Article, int64, error) {
 	var articles []models.Article
 	var count int64
-	query := db.WithContext(ctx).Model(&models.Article{}).Where("published = ?", true)
+	query := filteredArticles(ctx, opts)
 
-	// Count total published articles
+	// Count total articles matching the filter
 	if err := query.Count(&count).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Apply pagination and fetch articles with author information
 	offset := (page - 1) * pageSize
-	result := query.Preload("Author").Offset(offset).Limit(pageSize).Order("created_at DESC").Find(&articles)
+	result := query.Scopes(preloadArticle).
+		Offset(offset).Limit(pageSize).Order(articleOrder(opts.Sort)).Find(&articles)
 	if result.Error != nil {
 		return nil, 0, result.Error
 	}
@@ -74,39 +159,269 @@ func ListPublishedArticles(ctx *gin.Context, page, pageSize int) ([]models.Artic
 	return articles, count, nil
 }
 
+// ArticleCursorPage is one page of a keyset-paginated article listing. The
+// cursors are empty when there is nothing further in that direction, and
+// Total is only set when a count was requested.
+type ArticleCursorPage struct {
+	Articles   []models.Article
+	NextCursor string
+	PrevCursor string
+	Total      *int64
+}
+
+// ListArticlesByCursor returns up to limit articles matching opts, newest
+// first, starting after the position encoded in cursor (or from the start
+// if cursor is empty). Rows are ordered by (created_at, id) so pages stay
+// stable while articles are being added, which is why opts.Sort is not
+// supported here.
+func ListArticlesByCursor(ctx *gin.Context, opts ArticleListOptions, cursor string, limit int, withCount bool) (*ArticleCursorPage, error) {
+	if len(opts.Sort) > 0 {
+		return nil, &QueryError{"sort", "sorting is not supported with cursor pagination"}
+	}
+
+	position, err := decodeArticleCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &ArticleCursorPage{}
+	if withCount {
+		var count int64
+		if err := filteredArticles(ctx, opts).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		page.Total = &count
+	}
+
+	query := filteredArticles(ctx, opts).Scopes(preloadArticle)
+	backward := position != nil && position.Backward
+	switch {
+	case position == nil:
+		query = query.Order("created_at DESC, id DESC")
+	case backward:
+		query = query.Where("(created_at, id) > (?, ?)", position.CreatedAt, position.ID).
+			Order("created_at ASC, id ASC")
+	default:
+		query = query.Where("(created_at, id) < (?, ?)", position.CreatedAt, position.ID).
+			Order("created_at DESC, id DESC")
+	}
+
+	// One extra row tells whether there is more in the direction of travel
+	var articles []models.Article
+	if err := query.Limit(limit + 1).Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	hasMore := len(articles) > limit
+	if hasMore {
+		articles = articles[:limit]
+	}
+	if backward {
+		for i, j := 0, len(articles)-1; i < j; i, j = i+1, j-1 {
+			articles[i], articles[j] = articles[j], articles[i]
+		}
+	}
+	page.Articles = articles
+
+	if len(articles) == 0 {
+		return page, nil
+	}
+	first, last := &articles[0], &articles[len(articles)-1]
+	if (backward && hasMore) || (!backward && position != nil) {
+		page.PrevCursor = encodeArticleCursor(first, true)
+	}
+	if (!backward && hasMore) || backward {
+		page.NextCursor = encodeArticleCursor(last, false)
+	}
+	return page, nil
+}
+
+// publishedWindow restricts a query to articles that are live at the given time.
+// Approved articles with a due publish time are matched directly, so listings
+// stay correct even when the scheduler has not caught up yet. Articles hidden
+// pending moderation are not live.
+func publishedWindow(now time.Time) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		return tx.
+			Where("state = ? OR (state = ? AND publish_at IS NOT NULL AND publish_at <= ?)",
+				models.ArticleStatePublished, models.ArticleStateApproved, now).
+			Where("unpublish_at IS NULL OR unpublish_at > ?", now).
+			Where("hidden_at IS NULL AND held_at IS NULL")
+	}
+}
+
+// ArticleEditableColumns are the article columns authors can change directly
+var ArticleEditableColumns = []string{
+	"title", "slug", "content", "format", "blocks", "publish_at", "unpublish_at",
+	"excerpt", "visibility",
+}
+
+// UpdateArticle saves the editable fields of an article. An empty Slug
+// regenerates it from the title; the previous slug stays in the history.
+// The update only applies if the article is still at article.Version, and
+// fails with ErrVersionConflict otherwise.
 func UpdateArticle(ctx *gin.Context, article *models.Article) (*models.Article, error) {
+	return UpdateArticleColumns(ctx, article, ArticleEditableColumns)
+}
+
+// UpdateArticleColumns is UpdateArticle restricted to the given editable
+// columns, plus "tags" to replace the article's tags with article.Tags.
+// Changing the content, format or blocks renders the HTML again and
+// refreshes the content signature, and changing any of them or the excerpt
+// refreshes the description. New titles and content are screened like new
+// articles and have their keywords extracted again: a rejection fails the
+// update with a ScreeningRejectedError, and a hold hides the article until a
+// moderator reviews it. Like UpdateArticle, it fails with ErrVersionConflict
+// if the article is no longer at article.Version.
+func UpdateArticleColumns(ctx *gin.Context, article *models.Article, columns []string) (*models.Article, error) {
+	return updateArticleColumns(ctx, article, columns, nil)
+}
+
+// updateArticleColumns is UpdateArticleColumns with an extra step run in the
+// same transaction after the article has been updated
+func updateArticleColumns(ctx *gin.Context, article *models.Article, columns []string, also func(tx *gorm.DB) error) (*models.Article, error) {
 	var updatedArticle models.Article
 
-	// Check if article exists
-	result := db.WithContext(ctx).Where("id = ?", article.ID).First(&updatedArticle)
-	if result.Error != nil {
-		return nil, result.Error
+	values := map[string]interface{}{}
+	updateSlug := false
+	updateTags := false
+	for _, column := range columns {
+		switch column {
+		case "slug":
+			updateSlug = true
+		case "tags":
+			updateTags = true
+		case "title":
+			values[column] = article.Title
+		case "content":
+			values[column] = article.Content
+		case "format":
+			values[column] = article.Format
+		case "blocks":
+			values[column] = article.Blocks
+		case "publish_at":
+			values[column] = article.PublishAt
+		case "unpublish_at":
+			values[column] = article.UnpublishAt
+		case "excerpt":
+			values["custom_excerpt"] = article.CustomExcerpt
+		case "visibility":
+			values["visibility"] = article.Visibility
+			values["password_hash"] = article.PasswordHash
+		}
+	}
+	_, contentChanged := values["content"]
+	_, formatChanged := values["format"]
+	_, blocksChanged := values["blocks"]
+	_, excerptChanged := values["custom_excerpt"]
+	if contentChanged || formatChanged || blocksChanged {
+		renderArticleContent(article)
+		values["content"] = article.Content
+		values["content_html"] = article.ContentHTML
+	} else if excerptChanged {
+		describeArticleContent(article)
+	}
+	if contentChanged || formatChanged || blocksChanged || excerptChanged {
+		for column, value := range articleDescriptionValues(article) {
+			values[column] = value
+		}
 	}
 
-	// Update article fields
-	result = db.WithContext(ctx).Model(&updatedArticle).Updates(map[string]interface{}{
-		"title":     article.Title,
-		"content":   article.Content,
-		"published": article.Published,
+	// New titles and content are screened like new articles
+	var screened *models.ArticleScreening
+	_, titleChanged := values["title"]
+	if titleChanged || contentChanged || formatChanged || blocksChanged {
+		screened = screenArticle(ctx, article)
+	}
+	if screened != nil && screened.Verdict == models.ScreeningReject {
+		return nil, rejectScreenedArticle(ctx, screened)
+	}
+	if screened != nil && screened.Verdict == models.ScreeningHold {
+		values["held_at"] = db.NowFunc()
+	}
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Check if article exists
+		if err := tx.Where("id = ?", article.ID).First(&updatedArticle).Error; err != nil {
+			return err
+		}
+
+		// Update article fields
+		values["version"] = nextVersion
+		save := func(tx *gorm.DB) error {
+			if updateSlug {
+				values["slug"] = article.Slug
+			}
+			result := tx.Model(&models.Article{}).
+				Where("id = ? AND version = ?", article.ID, article.Version).
+				Updates(values)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrVersionConflict
+			}
+			return nil
+		}
+		if updateSlug {
+			if err := saveWithSlug(tx, article, article.Slug, save); err != nil {
+				return err
+			}
+			if err := recordSlug(tx, article); err != nil {
+				return err
+			}
+		} else if err := save(tx); err != nil {
+			return err
+		}
+
+		if screened != nil {
+			if err := tx.Create(screened).Error; err != nil {
+				return err
+			}
+		}
+
+		if contentChanged || formatChanged || blocksChanged {
+			if err := saveArticleSignature(tx, article); err != nil {
+				return err
+			}
+		}
+		if titleChanged || contentChanged || formatChanged || blocksChanged {
+			if err := saveArticleKeywords(tx, article); err != nil {
+				return err
+			}
+		}
+
+		if updateTags {
+			if err := setArticleTags(tx, article, articleTagNames(article)); err != nil {
+				return err
+			}
+		}
+		if also != nil {
+			return also(tx)
+		}
+		return nil
 	})
-	if result.Error != nil {
-		return nil, result.Error
+	if err != nil {
+		return nil, err
 	}
 
 	// Fetch the updated article with author information
-	db.WithContext(ctx).Preload("Author").Where("id = ?", article.ID).First(&updatedArticle)
+	db.WithContext(ctx).Scopes(preloadArticle).Where("id = ?", article.ID).First(&updatedArticle)
 	return &updatedArticle, nil
 }
 
-func DeleteArticle(ctx *gin.Context, id string) error {
-	result := db.WithContext(ctx).Where("id = ?", id).Delete(&models.Article{})
-	if result.Error != nil {
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		return errors.New("article not found")
-	}
-	return nil
+// DeleteArticle soft-deletes an article if it is still at the given version.
+// Articles in the trash leave the keyword corpus.
+func DeleteArticle(ctx *gin.Context, id string, version int64) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND version = ?", id, version).Delete(&models.Article{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+		return unindexArticles(tx, []string{id})
+	})
 }
 
 func SaveRecentlyViewedArticle(ctx *gin.Context, userID, articleID string) error {
@@ -0,0 +1,105 @@
+package database
+
+import (
+	"Praiseson6065/ocrolus-be/models"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrAlreadyCollaborator is returned when a user is invited to an article twice
+	ErrAlreadyCollaborator = errors.New("user is already a collaborator")
+	// ErrNotAuthor is returned when ownership is transferred by someone who
+	// is no longer the article's author
+	ErrNotAuthor = errors.New("user is not the article's author")
+)
+
+// ListCollaborators returns an article's collaborators, oldest first
+func ListCollaborators(ctx *gin.Context, articleID string) ([]models.ArticleCollaborator, error) {
+	var collaborators []models.ArticleCollaborator
+	err := db.WithContext(ctx).
+		Preload("User").
+		Where("article_id = ?", articleID).
+		Order("created_at ASC").
+		Find(&collaborators).Error
+	if err != nil {
+		return nil, err
+	}
+	return collaborators, nil
+}
+
+func AddCollaborator(ctx *gin.Context, collaborator *models.ArticleCollaborator) (*models.ArticleCollaborator, error) {
+	result := db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(collaborator)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrAlreadyCollaborator
+	}
+	return collaborator, nil
+}
+
+func UpdateCollaboratorRole(ctx *gin.Context, articleID, userID, role string) error {
+	result := db.WithContext(ctx).Model(&models.ArticleCollaborator{}).
+		Where("article_id = ? AND user_id = ?", articleID, userID).
+		Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("collaborator not found")
+	}
+	return nil
+}
+
+func RemoveCollaborator(ctx *gin.Context, articleID, userID string) error {
+	result := db.WithContext(ctx).
+		Where("article_id = ? AND user_id = ?", articleID, userID).
+		Delete(&models.ArticleCollaborator{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("collaborator not found")
+	}
+	return nil
+}
+
+// TransferArticleOwnership makes toUserID the article's author. The previous
+// author stays on as an owner collaborator.
+func TransferArticleOwnership(ctx *gin.Context, articleID, fromUserID, toUserID string) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var article models.Article
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", articleID).
+			First(&article).Error; err != nil {
+			return err
+		}
+		if article.AuthorID != fromUserID {
+			return ErrNotAuthor
+		}
+
+		if err := tx.Where("article_id = ? AND user_id = ?", articleID, toUserID).
+			Delete(&models.ArticleCollaborator{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&article).Updates(map[string]interface{}{
+			"author_id": toUserID,
+			"version":   nextVersion,
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&models.ArticleCollaborator{
+			ArticleID:   articleID,
+			UserID:      fromUserID,
+			Role:        models.CollaboratorOwner,
+			InvitedByID: fromUserID,
+		}).Error
+	})
+}
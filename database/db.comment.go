@@ -0,0 +1,117 @@
+package database
+
+import (
+	"Praiseson6065/ocrolus-be/models"
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// liveDescendant is a condition that holds when the comment aliased %[1]s
+// has a live reply anywhere below it, however deeply nested
+const liveDescendant = "EXISTS (WITH RECURSIVE descendants AS (" +
+	"SELECT reply.id, reply.deleted_at FROM comments AS reply WHERE reply.parent_id = %[1]s.id " +
+	"UNION ALL SELECT reply.id, reply.deleted_at FROM comments AS reply " +
+	"JOIN descendants ON reply.parent_id = descendants.id" +
+	") SELECT 1 FROM descendants WHERE descendants.deleted_at IS NULL)"
+
+// visibleComment is a condition that holds when the comment aliased %[1]s is
+// shown: it is live, or deleted with a live comment further down its thread
+const visibleComment = "(%[1]s.deleted_at IS NULL OR " + liveDescendant + ")"
+
+// replyCountSelect adds the number of visible direct replies to each comment
+var replyCountSelect = "comments.*, (SELECT COUNT(*) FROM comments AS replies " +
+	"WHERE replies.parent_id = comments.id AND " + fmt.Sprintf(visibleComment, "replies") + ") AS reply_count"
+
+// visibleComments keeps live comments and deleted comments that still have
+// live replies at any depth, so a thread never loses its shape
+func visibleComments(tx *gorm.DB) *gorm.DB {
+	return tx.Where(fmt.Sprintf(visibleComment, "comments"))
+}
+
+func CreateComment(ctx *gin.Context, comment *models.Comment) (*models.Comment, error) {
+	if err := db.WithContext(ctx).Create(comment).Error; err != nil {
+		return nil, err
+	}
+	return GetCommentByID(ctx, comment.ID)
+}
+
+func GetCommentByID(ctx *gin.Context, id string) (*models.Comment, error) {
+	var comment models.Comment
+	result := db.WithContext(ctx).Preload("Author").Where("id = ?", id).First(&comment)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("comment not found")
+		}
+		return nil, result.Error
+	}
+	return &comment, nil
+}
+
+// ListComments returns a page of top-level comments on an article, newest
+// first, each with its number of replies
+func ListComments(ctx *gin.Context, articleID string, page, pageSize int) ([]models.Comment, int64, error) {
+	query := db.WithContext(ctx).Unscoped().Model(&models.Comment{}).
+		Where("article_id = ? AND parent_id IS NULL", articleID).
+		Scopes(visibleComments)
+	return listComments(query, page, pageSize, "created_at DESC")
+}
+
+// ListReplies returns a page of direct replies to a comment, oldest first
+func ListReplies(ctx *gin.Context, articleID, parentID string, page, pageSize int) ([]models.Comment, int64, error) {
+	query := db.WithContext(ctx).Unscoped().Model(&models.Comment{}).
+		Where("article_id = ? AND parent_id = ?", articleID, parentID).
+		Scopes(visibleComments)
+	return listComments(query, page, pageSize, "created_at ASC")
+}
+
+func listComments(query *gorm.DB, page, pageSize int, order string) ([]models.Comment, int64, error) {
+	var comments []models.Comment
+	var count int64
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	result := query.Select(replyCountSelect).Preload("Author").
+		Offset(offset).Limit(pageSize).Order(order).Find(&comments)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return comments, count, nil
+}
+
+func UpdateComment(ctx *gin.Context, comment *models.Comment) (*models.Comment, error) {
+	result := db.WithContext(ctx).Model(&models.Comment{}).
+		Where("id = ?", comment.ID).
+		Updates(map[string]interface{}{
+			"body":      comment.Body,
+			"edited_at": comment.EditedAt,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return GetCommentByID(ctx, comment.ID)
+}
+
+func DeleteComment(ctx *gin.Context, id string) error {
+	result := db.WithContext(ctx).Where("id = ?", id).Delete(&models.Comment{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("comment not found")
+	}
+	return nil
+}
+
+// SetCommentsClosed opens or closes an article for new comments
+func SetCommentsClosed(ctx *gin.Context, articleID string, closed bool) error {
+	return db.WithContext(ctx).Model(&models.Article{}).
+		Where("id = ?", articleID).
+		Updates(map[string]interface{}{"comments_closed": closed, "version": nextVersion}).Error
+}
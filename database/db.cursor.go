@@ -0,0 +1,47 @@
+package database
+
+import (
+	"Praiseson6065/ocrolus-be/models"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// articleCursor marks a position in an article listing. Backward cursors
+// fetch the rows before the position instead of after it.
+type articleCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id"`
+	Backward  bool      `json:"b,omitempty"`
+}
+
+// encodeArticleCursor returns an opaque cursor positioned at article
+func encodeArticleCursor(article *models.Article, backward bool) string {
+	data, _ := json.Marshal(articleCursor{
+		CreatedAt: article.CreatedAt,
+		ID:        article.ID,
+		Backward:  backward,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeArticleCursor parses a cursor made by encodeArticleCursor. An empty
+// cursor means the start of the listing and decodes to nil.
+func decodeArticleCursor(cursor string) (*articleCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var position articleCursor
+	if err := json.Unmarshal(data, &position); err != nil || position.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &position, nil
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"fmt"
 	"log"
+	"testing"
 
 	"Praiseson6065/ocrolus-be/config"
 	"Praiseson6065/ocrolus-be/models"
@@ -43,22 +44,114 @@ func ConnectDB() error {
 func MigrateDB() error {
 	log.Println("Running database migrations...")
 
+	// Screening holds move to their own column the first time it is added
+	splitHolds := db.Migrator().HasTable(&models.Article{}) &&
+		!db.Migrator().HasColumn(&models.Article{}, "held_at")
+
 	// Auto migrate all models
 	err := db.AutoMigrate(
 		&models.User{},
+		&models.Tag{},
 		&models.Article{},
 		&models.RecentlyViewedArticle{},
+		&models.ArticleTransition{},
+		&models.ArticleSlug{},
+		&models.Comment{},
+		&models.ArticleReaction{},
+		&models.ArticleReactionCount{},
+		&models.ReadingList{},
+		&models.ReadingListItem{},
+		&models.Series{},
+		&models.SeriesPart{},
+		&models.ArticleCollaborator{},
+		&models.ArticleSuggestion{},
+		&models.ArticleEditLock{},
+		&models.ArticlePreviewLink{},
+		&models.ContentReport{},
+		&models.ModerationAction{},
+		&models.ArticleScreening{},
+		&models.SpamClass{},
+		&models.SpamToken{},
+		&models.ArticleSignature{},
+		&models.ArticleSignatureBucket{},
+		&models.ArticleTerm{},
+		&models.KeywordCorpus{},
 	)
 
 	if err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	if err := migrateDefaultReadingLists(); err != nil {
+		return fmt.Errorf("failed to migrate default reading lists: %w", err)
+	}
+
+	if splitHolds {
+		if err := migrateScreeningHolds(); err != nil {
+			return fmt.Errorf("failed to migrate screening holds: %w", err)
+		}
+	}
+
+	if err := migrateArticleStates(); err != nil {
+		return fmt.Errorf("failed to migrate article states: %w", err)
+	}
+
+	if err := migrateArticleSlugs(); err != nil {
+		return fmt.Errorf("failed to migrate article slugs: %w", err)
+	}
+
+	if err := migrateArticleHTML(); err != nil {
+		return fmt.Errorf("failed to render article HTML: %w", err)
+	}
+
+	if err := migrateArticleDescriptions(); err != nil {
+		return fmt.Errorf("failed to describe articles: %w", err)
+	}
+
+	if err := migrateArticleSignatures(); err != nil {
+		return fmt.Errorf("failed to compute article signatures: %w", err)
+	}
+
+	if err := migrateKeywordCorpus(); err != nil {
+		return fmt.Errorf("failed to count the keyword corpus: %w", err)
+	}
+
+	if err := migrateArticleKeywords(); err != nil {
+		return fmt.Errorf("failed to extract article keywords: %w", err)
+	}
+
 	log.Println("Database migrations completed successfully")
 	return nil
 }
 
+// migrateArticleStates maps the legacy published flag onto editorial states
+// and drops the column. Scheduled articles become approved so they still go
+// live at their publish time.
+func migrateArticleStates() error {
+	if !db.Migrator().HasColumn(&models.Article{}, "published") {
+		return nil
+	}
+
+	log.Println("Migrating published flag to article states...")
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("UPDATE articles SET state = ? WHERE published = true",
+			models.ArticleStatePublished).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("UPDATE articles SET state = ? WHERE published = false AND publish_at IS NOT NULL",
+			models.ArticleStateApproved).Error; err != nil {
+			return err
+		}
+		return tx.Migrator().DropColumn(&models.Article{}, "published")
+	})
+}
+
 func init() {
+	// Tests of the helpers in this package run without a database
+	if testing.Testing() {
+		return
+	}
+
 	// Connect to the database
 	if err := ConnectDB(); err != nil {
 		log.Fatalf("Database connection failed: %v", err)
@@ -0,0 +1,255 @@
+package database
+
+import (
+	"log"
+
+	"Praiseson6065/ocrolus-be/models"
+	"Praiseson6065/ocrolus-be/util"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// articleKeywordCount is the number of keywords stored with an article
+const articleKeywordCount = 10
+
+// keywordCorpusID is the ID of the single KeywordCorpus row
+const keywordCorpusID = 1
+
+// TagSuggestion is a keyword proposed as a tag. Tag is the existing tag
+// with the same slug, if there is one.
+type TagSuggestion struct {
+	Keyword string
+	Score   float64
+	Tag     *models.Tag
+}
+
+// articleKeywordTerms counts the candidate keywords of an article's title
+// and rendered text
+func articleKeywordTerms(article *models.Article) map[string]int {
+	return util.KeywordTerms(article.Title, util.HTMLToText(article.ContentHTML))
+}
+
+// rankKeywords weighs the terms of a document against the articles indexed
+// so far
+func rankKeywords(tx *gorm.DB, terms map[string]int, limit int) ([]util.Keyword, error) {
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	var corpus models.KeywordCorpus
+	if err := tx.Where("id = ?", keywordCorpusID).Limit(1).Find(&corpus).Error; err != nil {
+		return nil, err
+	}
+	documents := corpus.Documents
+
+	names := make([]string, 0, len(terms))
+	for term := range terms {
+		names = append(names, term)
+	}
+	var rows []struct {
+		Term      string
+		Documents int64
+	}
+	if err := tx.Model(&models.ArticleTerm{}).
+		Select("term, COUNT(*) AS documents").
+		Where("term IN ?", names).
+		Group("term").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	frequencies := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		frequencies[row.Term] = row.Documents
+	}
+	return util.RankKeywords(terms, frequencies, documents, limit), nil
+}
+
+// countKeywordDocuments adds delta to the number of articles in the keyword
+// corpus
+func countKeywordDocuments(tx *gorm.DB, delta int64) error {
+	if delta == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"documents": gorm.Expr("keyword_corpus.documents + ?", delta)}),
+	}).Create(&models.KeywordCorpus{ID: keywordCorpusID, Documents: delta}).Error
+}
+
+// indexArticleTerms replaces the terms an article contributes to the
+// keyword corpus
+func indexArticleTerms(tx *gorm.DB, article *models.Article, terms map[string]int) error {
+	result := tx.Where("article_id = ?", article.ID).Delete(&models.ArticleTerm{})
+	if result.Error != nil {
+		return result.Error
+	}
+	var delta int64
+	if result.RowsAffected > 0 {
+		delta--
+	}
+	if len(terms) > 0 {
+		delta++
+	}
+	if err := countKeywordDocuments(tx, delta); err != nil {
+		return err
+	}
+	if len(terms) == 0 {
+		return nil
+	}
+
+	rows := make([]models.ArticleTerm, 0, len(terms))
+	for term := range terms {
+		rows = append(rows, models.ArticleTerm{ArticleID: article.ID, Term: term})
+	}
+	return tx.CreateInBatches(&rows, 500).Error
+}
+
+// unindexArticles removes the terms of articles from the keyword corpus,
+// for articles that are trashed or purged
+func unindexArticles(tx *gorm.DB, ids []string) error {
+	var indexed int64
+	if err := tx.Model(&models.ArticleTerm{}).
+		Where("article_id IN ?", ids).
+		Distinct("article_id").
+		Count(&indexed).Error; err != nil {
+		return err
+	}
+	if indexed == 0 {
+		return nil
+	}
+	if err := tx.Where("article_id IN ?", ids).Delete(&models.ArticleTerm{}).Error; err != nil {
+		return err
+	}
+	return countKeywordDocuments(tx, -indexed)
+}
+
+// updateArticleKeywords ranks the terms of an article and stores the best
+// ones as its keywords. The keywords are derived data, so the article's
+// version and update time are left alone.
+func updateArticleKeywords(tx *gorm.DB, article *models.Article, terms map[string]int) error {
+	ranked, err := rankKeywords(tx, terms, articleKeywordCount)
+	if err != nil {
+		return err
+	}
+
+	keywords := make(models.Keywords, len(ranked))
+	for i, keyword := range ranked {
+		keywords[i] = keyword.Term
+	}
+	article.Keywords = keywords
+	return tx.Unscoped().Model(&models.Article{}).
+		Where("id = ?", article.ID).
+		UpdateColumn("keywords", keywords).Error
+}
+
+// saveArticleKeywords indexes an article's terms in the keyword corpus and
+// refreshes its keywords. Other articles keep the keywords computed when
+// they were last saved.
+func saveArticleKeywords(tx *gorm.DB, article *models.Article) error {
+	terms := articleKeywordTerms(article)
+	if err := indexArticleTerms(tx, article, terms); err != nil {
+		return err
+	}
+	return updateArticleKeywords(tx, article, terms)
+}
+
+// SuggestTags extracts the keywords of a draft that is not necessarily
+// saved. Keywords matching tags already set on the draft are left out, and
+// those matching other existing tags come with the tag.
+func SuggestTags(ctx *gin.Context, article *models.Article, limit int) ([]TagSuggestion, error) {
+	renderArticleContent(article)
+	terms := articleKeywordTerms(article)
+
+	chosen := map[string]bool{}
+	for _, tag := range article.Tags {
+		chosen[util.Slugify(tag.Name)] = true
+	}
+	for term := range terms {
+		if chosen[util.Slugify(term)] {
+			delete(terms, term)
+		}
+	}
+
+	ranked, err := rankKeywords(db.WithContext(ctx), terms, limit)
+	if err != nil || len(ranked) == 0 {
+		return nil, err
+	}
+
+	slugs := make([]string, len(ranked))
+	for i, keyword := range ranked {
+		slugs[i] = util.Slugify(keyword.Term)
+	}
+	var tags []models.Tag
+	if err := db.WithContext(ctx).Where("slug IN ?", slugs).Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	bySlug := make(map[string]*models.Tag, len(tags))
+	for i := range tags {
+		bySlug[tags[i].Slug] = &tags[i]
+	}
+
+	suggestions := make([]TagSuggestion, len(ranked))
+	for i, keyword := range ranked {
+		suggestions[i] = TagSuggestion{
+			Keyword: keyword.Term,
+			Score:   keyword.Score,
+			Tag:     bySlug[slugs[i]],
+		}
+	}
+	return suggestions, nil
+}
+
+// migrateKeywordCorpus sets up the document count of a corpus indexed
+// before it was kept, dropping the terms of articles in the trash first
+func migrateKeywordCorpus() error {
+	var rows int64
+	if err := db.Model(&models.KeywordCorpus{}).Count(&rows).Error; err != nil {
+		return err
+	}
+	if rows > 0 {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM article_terms WHERE article_id IN (SELECT id FROM articles WHERE deleted_at IS NOT NULL)").Error; err != nil {
+			return err
+		}
+		var documents int64
+		if err := tx.Model(&models.ArticleTerm{}).Distinct("article_id").Count(&documents).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.KeywordCorpus{ID: keywordCorpusID, Documents: documents}).Error
+	})
+}
+
+// migrateArticleKeywords extracts the keywords of articles saved before
+// they were stored. All terms are indexed before any article is ranked, so
+// that every article is weighed against the whole corpus. Articles in the
+// trash are left for when they are restored.
+func migrateArticleKeywords() error {
+	var articles []models.Article
+	if err := db.Where("keywords IS NULL").Find(&articles).Error; err != nil {
+		return err
+	}
+
+	terms := make([]map[string]int, len(articles))
+	for i := range articles {
+		terms[i] = articleKeywordTerms(&articles[i])
+		if err := indexArticleTerms(db, &articles[i], terms[i]); err != nil {
+			return err
+		}
+	}
+	for i := range articles {
+		if err := updateArticleKeywords(db, &articles[i], terms[i]); err != nil {
+			return err
+		}
+	}
+
+	if len(articles) > 0 {
+		log.Printf("Extracted keywords for %d articles", len(articles))
+	}
+	return nil
+}
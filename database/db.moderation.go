@@ -0,0 +1,421 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"Praiseson6065/ocrolus-be/config"
+	"Praiseson6065/ocrolus-be/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrAlreadyReported is returned when a user reports content they already
+// have an open report on
+var ErrAlreadyReported = errors.New("content has already been reported")
+
+// ErrReportClosed is returned when a report that has already been resolved
+// or dismissed is acted on again
+var ErrReportClosed = errors.New("report is no longer open")
+
+// ErrNotSuspended is returned when lifting the suspension of a user who is
+// not suspended
+var ErrNotSuspended = errors.New("user is not suspended")
+
+// openReportStatuses are the statuses of reports still awaiting a decision
+var openReportStatuses = []string{models.ReportOpen, models.ReportInReview}
+
+// preloadReport loads the users shown with every report
+func preloadReport(tx *gorm.DB) *gorm.DB {
+	return tx.Preload("Reporter").Preload("Assignee").Preload("ResolvedBy")
+}
+
+// CreateReport files a report against an article. Once as many different
+// people as the configured threshold have open reports on the article, it is
+// hidden until a moderator resolves them. Reports on the same article are
+// serialized on the article's row so the threshold is only crossed once.
+func CreateReport(ctx *gin.Context, report *models.ContentReport) (*models.ContentReport, error) {
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var article models.Article
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", report.TargetID).First(&article).Error; err != nil {
+			return err
+		}
+
+		var existing int64
+		if err := tx.Model(&models.ContentReport{}).
+			Where("target_type = ? AND target_id = ? AND reporter_id = ? AND status IN ?",
+				report.TargetType, report.TargetID, report.ReporterID, openReportStatuses).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return ErrAlreadyReported
+		}
+
+		if err := tx.Create(report).Error; err != nil {
+			return err
+		}
+
+		threshold := config.Config.Moderation.HideThreshold
+		if threshold <= 0 || article.HiddenAt != nil {
+			return nil
+		}
+
+		var reporters int64
+		if err := tx.Model(&models.ContentReport{}).
+			Where("target_type = ? AND target_id = ? AND status IN ?",
+				report.TargetType, report.TargetID, openReportStatuses).
+			Distinct("reporter_id").
+			Count(&reporters).Error; err != nil {
+			return err
+		}
+		if reporters < int64(threshold) {
+			return nil
+		}
+
+		if err := tx.Model(&models.Article{}).
+			Where("id = ?", article.ID).
+			Updates(map[string]interface{}{"hidden_at": db.NowFunc(), "version": nextVersion}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.ModerationAction{
+			ReportID:     &report.ID,
+			Action:       models.ModerationAutoHide,
+			TargetType:   report.TargetType,
+			TargetID:     report.TargetID,
+			TargetUserID: report.TargetUserID,
+			Note:         "Hidden after reports from several readers",
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return GetReportByID(ctx, report.ID)
+}
+
+// GetReportByID returns a report with its reporter, assignee and resolver
+func GetReportByID(ctx *gin.Context, id string) (*models.ContentReport, error) {
+	var report models.ContentReport
+	result := db.WithContext(ctx).Scopes(preloadReport).Where("id = ?", id).First(&report)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("report not found")
+		}
+		return nil, result.Error
+	}
+	return &report, nil
+}
+
+// ReportListOptions narrows the reports returned by ListReports
+type ReportListOptions struct {
+	Statuses   []string
+	Reason     string
+	TargetType string
+	TargetID   string
+	AssigneeID string
+	Unassigned bool
+}
+
+// ListReports returns a page of the moderation queue, oldest first so that
+// reports are worked off in the order they came in
+func ListReports(ctx *gin.Context, page, pageSize int, opts ReportListOptions) ([]models.ContentReport, int64, error) {
+	var reports []models.ContentReport
+	var count int64
+	query := db.WithContext(ctx).Model(&models.ContentReport{})
+
+	if len(opts.Statuses) > 0 {
+		query = query.Where("status IN ?", opts.Statuses)
+	}
+	if opts.Reason != "" {
+		query = query.Where("reason = ?", opts.Reason)
+	}
+	if opts.TargetType != "" {
+		query = query.Where("target_type = ?", opts.TargetType)
+	}
+	if opts.TargetID != "" {
+		query = query.Where("target_id = ?", opts.TargetID)
+	}
+	if opts.AssigneeID != "" {
+		query = query.Where("assignee_id = ?", opts.AssigneeID)
+	}
+	if opts.Unassigned {
+		query = query.Where("assignee_id IS NULL")
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	result := query.Scopes(preloadReport).
+		Offset(offset).Limit(pageSize).Order("created_at ASC, id ASC").Find(&reports)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return reports, count, nil
+}
+
+// AssignReport hands an open report to a moderator, or back to the queue
+// when assigneeID is nil, and records who did it
+func AssignReport(ctx *gin.Context, report *models.ContentReport, assigneeID *string, actorID string) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		status, action, note := models.ReportInReview, models.ModerationAssign, ""
+		if assigneeID == nil {
+			status, action = models.ReportOpen, models.ModerationUnassign
+		} else {
+			note = "Assigned to " + *assigneeID
+		}
+
+		result := tx.Model(&models.ContentReport{}).
+			Where("id = ? AND status IN ?", report.ID, openReportStatuses).
+			Updates(map[string]interface{}{"assignee_id": assigneeID, "status": status})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrReportClosed
+		}
+
+		return tx.Create(&models.ModerationAction{
+			ReportID:     &report.ID,
+			ActorID:      &actorID,
+			Action:       action,
+			TargetType:   report.TargetType,
+			TargetID:     report.TargetID,
+			TargetUserID: report.TargetUserID,
+			Note:         note,
+		}).Error
+	})
+}
+
+// ReportResolution is a moderator's decision on a report
+type ReportResolution struct {
+	Action         string
+	ActorID        string
+	Note           string
+	SuspendedUntil *time.Time // only for suspensions; nil suspends indefinitely
+}
+
+// ResolveReport carries out a moderator's decision on a report and closes it
+// together with every other open report on the same content. Dismissing a
+// report closes it as dismissed; any other action closes it as resolved.
+// Content hidden after reports becomes visible again when the report is
+// dismissed or its author warned, unless content screening holds it too. It stays hidden when its author is
+// suspended, and when it is deleted so that restoring it from the trash does
+// not bring it back. The decision
+// fails with ErrReportClosed if the report was closed concurrently, and an
+// unpublish fails with ErrStateConflict if the article is not published.
+func ResolveReport(ctx *gin.Context, report *models.ContentReport, resolution ReportResolution) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := db.NowFunc()
+		status := models.ReportResolved
+		if resolution.Action == models.ResolutionDismiss {
+			status = models.ReportDismissed
+		}
+
+		// Claim the report first so that two moderators cannot both act on it
+		result := tx.Model(&models.ContentReport{}).
+			Where("id = ? AND status IN ?", report.ID, openReportStatuses).
+			Update("status", status)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrReportClosed
+		}
+
+		if err := tx.Model(&models.ContentReport{}).
+			Where("target_type = ? AND target_id = ? AND (id = ? OR status IN ?)",
+				report.TargetType, report.TargetID, report.ID, openReportStatuses).
+			Updates(map[string]interface{}{
+				"status":          status,
+				"resolution":      resolution.Action,
+				"resolution_note": resolution.Note,
+				"resolved_by_id":  resolution.ActorID,
+				"resolved_at":     now,
+			}).Error; err != nil {
+			return err
+		}
+
+		if err := learnFromResolution(tx, report, resolution.Action); err != nil {
+			return err
+		}
+		if err := applyResolution(tx, report, resolution); err != nil {
+			return err
+		}
+
+		return tx.Create(&models.ModerationAction{
+			ReportID:     &report.ID,
+			ActorID:      &resolution.ActorID,
+			Action:       resolution.Action,
+			TargetType:   report.TargetType,
+			TargetID:     report.TargetID,
+			TargetUserID: report.TargetUserID,
+			Note:         resolution.Note,
+		}).Error
+	})
+}
+
+// applyResolution makes the changes a resolution calls for to the reported
+// content and its author
+func applyResolution(tx *gorm.DB, report *models.ContentReport, resolution ReportResolution) error {
+	switch resolution.Action {
+	case models.ResolutionUnpublish:
+		result := tx.Model(&models.Article{}).
+			Where("id = ? AND state = ?", report.TargetID, models.ArticleStatePublished).
+			Updates(map[string]interface{}{
+				"state":        models.ArticleStateDraft,
+				"unpublish_at": nil,
+				"hidden_at":    nil,
+				"version":      nextVersion,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrStateConflict
+		}
+		return tx.Create(&models.ArticleTransition{
+			ArticleID: report.TargetID,
+			FromState: models.ArticleStatePublished,
+			ToState:   models.ArticleStateDraft,
+			ActorID:   &resolution.ActorID,
+			Comment:   "Unpublished by a moderator: " + resolution.Note,
+		}).Error
+
+	case models.ResolutionDelete:
+		if err := tx.Model(&models.Article{}).
+			Where("id = ?", report.TargetID).
+			Updates(map[string]interface{}{"hidden_at": db.NowFunc(), "version": nextVersion}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id = ?", report.TargetID).Delete(&models.Article{}).Error; err != nil {
+			return err
+		}
+		return unindexArticles(tx, []string{report.TargetID})
+
+	case models.ResolutionSuspend:
+		if err := tx.Model(&models.User{}).
+			Where("id = ?", report.TargetUserID).
+			Updates(map[string]interface{}{
+				"suspended_at":    db.NowFunc(),
+				"suspended_until": resolution.SuspendedUntil,
+				"version":         nextVersion,
+			}).Error; err != nil {
+			return err
+		}
+		// The content stays hidden: suspending its author upholds the reports
+		return nil
+	}
+
+	// Dismissed or with a warning, the content may stay up and no longer
+	// needs hiding
+	return tx.Model(&models.Article{}).
+		Where("id = ? AND hidden_at IS NOT NULL", report.TargetID).
+		Updates(map[string]interface{}{"hidden_at": nil, "version": nextVersion}).Error
+}
+
+// LiftSuspension ends a user's suspension early, or at all for suspensions
+// without an end, and records who did it. It fails with ErrNotSuspended if
+// the user is not currently suspended.
+func LiftSuspension(ctx *gin.Context, userID, actorID, note string) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := db.NowFunc()
+		result := tx.Model(&models.User{}).
+			Where("id = ? AND suspended_at IS NOT NULL AND (suspended_until IS NULL OR suspended_until > ?)", userID, now).
+			Updates(map[string]interface{}{
+				"suspended_at":    nil,
+				"suspended_until": nil,
+				"version":         nextVersion,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotSuspended
+		}
+
+		return tx.Create(&models.ModerationAction{
+			ActorID:      &actorID,
+			Action:       models.ModerationLiftSuspension,
+			TargetType:   models.ModerationTargetUser,
+			TargetID:     userID,
+			TargetUserID: userID,
+			Note:         note,
+		}).Error
+	})
+}
+
+// learnFromResolution trains the spam classifier on decisions about
+// articles reported as spam: dismissing the report marks the article as
+// legitimate, taking it down or suspending its author marks it as spam
+func learnFromResolution(tx *gorm.DB, report *models.ContentReport, action string) error {
+	if report.Reason != models.ReportReasonSpam || report.TargetType != models.ReportTargetArticle {
+		return nil
+	}
+
+	var class string
+	switch action {
+	case models.ResolutionDismiss:
+		class = models.SpamClassHam
+	case models.ResolutionUnpublish, models.ResolutionDelete, models.ResolutionSuspend:
+		class = models.SpamClassSpam
+	default:
+		return nil
+	}
+
+	var article models.Article
+	if err := tx.Unscoped().Where("id = ?", report.TargetID).First(&article).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	return trainSpamFilter(tx, article.ID, screeningContent(&article), class)
+}
+
+// ModerationActionListOptions narrows the entries returned by
+// ListModerationActions
+type ModerationActionListOptions struct {
+	ReportID     string
+	TargetID     string
+	TargetUserID string
+	ActorID      string
+}
+
+// ListModerationActions returns a page of the moderation log, newest first
+func ListModerationActions(ctx *gin.Context, page, pageSize int, opts ModerationActionListOptions) ([]models.ModerationAction, int64, error) {
+	var actions []models.ModerationAction
+	var count int64
+	query := db.WithContext(ctx).Model(&models.ModerationAction{})
+
+	if opts.ReportID != "" {
+		query = query.Where("report_id = ?", opts.ReportID)
+	}
+	if opts.TargetID != "" {
+		query = query.Where("target_id = ?", opts.TargetID)
+	}
+	if opts.TargetUserID != "" {
+		query = query.Where("target_user_id = ?", opts.TargetUserID)
+	}
+	if opts.ActorID != "" {
+		query = query.Where("actor_id = ?", opts.ActorID)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	result := query.Preload("Actor").
+		Offset(offset).Limit(pageSize).Order("created_at DESC, id DESC").Find(&actions)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return actions, count, nil
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"errors"
+
+	"Praiseson6065/ocrolus-be/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ErrPreviewLinkInactive is returned when a preview link has expired, been
+// revoked or does not exist
+var ErrPreviewLinkInactive = errors.New("preview link is no longer active")
+
+func CreatePreviewLink(ctx *gin.Context, link *models.ArticlePreviewLink) (*models.ArticlePreviewLink, error) {
+	if err := db.WithContext(ctx).Create(link).Error; err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// ListPreviewLinks returns every preview link of an article, newest first,
+// including expired and revoked ones so their usage stays visible
+func ListPreviewLinks(ctx *gin.Context, articleID string) ([]models.ArticlePreviewLink, error) {
+	var links []models.ArticlePreviewLink
+	err := db.WithContext(ctx).
+		Preload("CreatedBy").
+		Where("article_id = ?", articleID).
+		Order("created_at DESC").
+		Find(&links).Error
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// RevokePreviewLink stops a preview link from working. Revoking a link twice
+// keeps the original revocation time.
+func RevokePreviewLink(ctx *gin.Context, articleID, linkID string) error {
+	var link models.ArticlePreviewLink
+	if err := db.WithContext(ctx).Where("id = ? AND article_id = ?", linkID, articleID).First(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("preview link not found")
+		}
+		return err
+	}
+	return db.WithContext(ctx).Model(&models.ArticlePreviewLink{}).
+		Where("id = ? AND revoked_at IS NULL", linkID).
+		Update("revoked_at", db.NowFunc()).Error
+}
+
+// GetActivePreviewLink returns a preview link that is neither revoked nor
+// expired, or ErrPreviewLinkInactive
+func GetActivePreviewLink(ctx *gin.Context, linkID string) (*models.ArticlePreviewLink, error) {
+	var link models.ArticlePreviewLink
+	err := db.WithContext(ctx).
+		Where("id = ? AND revoked_at IS NULL AND expires_at > NOW()", linkID).
+		First(&link).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrPreviewLinkInactive
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// UsePreviewLink records one use of an active preview link and returns it.
+// It is called once the preview is about to be served. The check and the
+// count happen in one statement so a link revoked at the same moment is not
+// counted.
+func UsePreviewLink(ctx *gin.Context, linkID string) (*models.ArticlePreviewLink, error) {
+	result := db.WithContext(ctx).Model(&models.ArticlePreviewLink{}).
+		Where("id = ? AND revoked_at IS NULL AND expires_at > NOW()", linkID).
+		Updates(map[string]interface{}{
+			"use_count":    gorm.Expr("use_count + 1"),
+			"last_used_at": gorm.Expr("NOW()"),
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrPreviewLinkInactive
+	}
+
+	var link models.ArticlePreviewLink
+	if err := db.WithContext(ctx).Where("id = ?", linkID).First(&link).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"Praiseson6065/ocrolus-be/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AddReaction records a reaction if the user has not already left it. The
+// counter is only bumped when a row was actually inserted, so repeated or
+// concurrent requests cannot inflate it.
+func AddReaction(ctx *gin.Context, articleID, userID, reactionType string) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.ArticleReaction{
+			ArticleID: articleID,
+			UserID:    userID,
+			Type:      reactionType,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "article_id"}, {Name: "type"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"count": gorm.Expr("article_reaction_counts.count + 1")}),
+		}).Create(&models.ArticleReactionCount{
+			ArticleID: articleID,
+			Type:      reactionType,
+			Count:     1,
+		}).Error
+	})
+}
+
+// RemoveReaction deletes a reaction if present and decrements its counter
+func RemoveReaction(ctx *gin.Context, articleID, userID, reactionType string) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("article_id = ? AND user_id = ? AND type = ?", articleID, userID, reactionType).
+			Delete(&models.ArticleReaction{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		return tx.Model(&models.ArticleReactionCount{}).
+			Where("article_id = ? AND type = ? AND count > 0", articleID, reactionType).
+			Update("count", gorm.Expr("count - 1")).Error
+	})
+}
+
+// GetReactionCounts returns reaction totals keyed by article ID and type
+func GetReactionCounts(ctx *gin.Context, articleIDs []string) (map[string]map[string]int64, error) {
+	counts := make(map[string]map[string]int64, len(articleIDs))
+	if len(articleIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []models.ArticleReactionCount
+	if err := db.WithContext(ctx).
+		Where("article_id IN ? AND count > 0", articleIDs).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if counts[row.ArticleID] == nil {
+			counts[row.ArticleID] = map[string]int64{}
+		}
+		counts[row.ArticleID][row.Type] = row.Count
+	}
+	return counts, nil
+}
+
+// GetUserReactions returns the reaction types a user left, keyed by article ID
+func GetUserReactions(ctx *gin.Context, userID string, articleIDs []string) (map[string][]string, error) {
+	reactions := make(map[string][]string)
+	if userID == "" || len(articleIDs) == 0 {
+		return reactions, nil
+	}
+
+	var rows []models.ArticleReaction
+	if err := db.WithContext(ctx).
+		Where("user_id = ? AND article_id IN ?", userID, articleIDs).
+		Order("created_at ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		reactions[row.ArticleID] = append(reactions[row.ArticleID], row.Type)
+	}
+	return reactions, nil
+}
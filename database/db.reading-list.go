@@ -0,0 +1,274 @@
+package database
+
+import (
+	"Praiseson6065/ocrolus-be/models"
+	"errors"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrAlreadyInList is returned when an article is added to a list twice
+	ErrAlreadyInList = errors.New("article is already in this list")
+	// ErrInvalidOrder is returned when a reorder does not name every item exactly once
+	ErrInvalidOrder = errors.New("order must list every item exactly once")
+)
+
+func CreateReadingList(ctx *gin.Context, list *models.ReadingList) (*models.ReadingList, error) {
+	if err := db.WithContext(ctx).Create(list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetReadingListByID returns a list with its items in order
+func GetReadingListByID(ctx *gin.Context, id string) (*models.ReadingList, error) {
+	var list models.ReadingList
+	result := db.WithContext(ctx).
+		Preload("User").
+		Preload("Items", func(tx *gorm.DB) *gorm.DB {
+			return tx.Order("position ASC")
+		}).
+		Preload("Items.Article.Author").
+		Preload("Items.Article.Collaborators").
+		Where("id = ?", id).
+		First(&list)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("reading list not found")
+		}
+		return nil, result.Error
+	}
+	return &list, nil
+}
+
+// ListReadingLists returns a user's lists with their item counts
+func ListReadingLists(ctx *gin.Context, userID string) ([]models.ReadingList, error) {
+	var lists []models.ReadingList
+	err := db.WithContext(ctx).
+		Select("reading_lists.*, (SELECT COUNT(*) FROM reading_list_items WHERE reading_list_items.list_id = reading_lists.id) AS item_count").
+		Where("user_id = ?", userID).
+		Order("is_default DESC, created_at ASC").
+		Find(&lists).Error
+	if err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+func UpdateReadingList(ctx *gin.Context, list *models.ReadingList) error {
+	return db.WithContext(ctx).Model(&models.ReadingList{}).
+		Where("id = ?", list.ID).
+		Updates(map[string]interface{}{
+			"name":        list.Name,
+			"description": list.Description,
+			"is_public":   list.IsPublic,
+		}).Error
+}
+
+// DeleteReadingList removes a list together with its items
+func DeleteReadingList(ctx *gin.Context, id string) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("list_id = ?", id).Delete(&models.ReadingListItem{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", id).Delete(&models.ReadingList{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("reading list not found")
+		}
+		return nil
+	})
+}
+
+// GetOrCreateDefaultReadingList returns the user's bookmarks list, creating
+// it on first use. Concurrent first bookmarks may both try to create it; the
+// unique index on default lists keeps one and the other reads it back.
+func GetOrCreateDefaultReadingList(ctx *gin.Context, userID string) (*models.ReadingList, error) {
+	var list models.ReadingList
+	err := db.WithContext(ctx).Where("user_id = ? AND is_default = ?", userID, true).Limit(1).Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	if list.ID != "" {
+		return &list, nil
+	}
+
+	created := models.ReadingList{UserID: userID, Name: models.DefaultReadingListName, IsDefault: true}
+	if err := db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&created).Error; err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Where("user_id = ? AND is_default = ?", userID, true).First(&list).Error; err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// migrateDefaultReadingLists adds the index allowing a single live default
+// list per user. Duplicates created before it existed become ordinary lists,
+// keeping the oldest as the default, so no bookmarks are lost.
+func migrateDefaultReadingLists() error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec("UPDATE reading_lists SET is_default = false " +
+			"WHERE is_default AND deleted_at IS NULL AND id NOT IN (" +
+			"SELECT DISTINCT ON (user_id) id FROM reading_lists " +
+			"WHERE is_default AND deleted_at IS NULL ORDER BY user_id, created_at, id)")
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("Demoted %d duplicate default reading lists", result.RowsAffected)
+		}
+		return tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_reading_lists_default " +
+			"ON reading_lists (user_id) WHERE is_default AND deleted_at IS NULL").Error
+	})
+}
+
+// lockReadingList takes a row lock on a list so that position changes to its
+// items are serialized
+func lockReadingList(tx *gorm.DB, listID string) error {
+	var list models.ReadingList
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", listID).
+		First(&list).Error
+}
+
+// AddReadingListItem appends an article to the end of a list
+func AddReadingListItem(ctx *gin.Context, listID, articleID, note string) (*models.ReadingListItem, error) {
+	item := &models.ReadingListItem{
+		ListID:    listID,
+		ArticleID: articleID,
+		Note:      note,
+	}
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockReadingList(tx, listID); err != nil {
+			return err
+		}
+
+		var existing int64
+		if err := tx.Model(&models.ReadingListItem{}).
+			Where("list_id = ? AND article_id = ?", listID, articleID).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return ErrAlreadyInList
+		}
+
+		var last int
+		if err := tx.Model(&models.ReadingListItem{}).
+			Where("list_id = ?", listID).
+			Select("COALESCE(MAX(position), 0)").
+			Scan(&last).Error; err != nil {
+			return err
+		}
+
+		item.Position = last + 1
+		return tx.Create(item).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func UpdateReadingListItemNote(ctx *gin.Context, listID, itemID, note string) error {
+	result := db.WithContext(ctx).Model(&models.ReadingListItem{}).
+		Where("id = ? AND list_id = ?", itemID, listID).
+		Update("note", note)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("item not found")
+	}
+	return nil
+}
+
+// RemoveReadingListItem deletes an item from a list
+func RemoveReadingListItem(ctx *gin.Context, listID, itemID string) error {
+	return removeReadingListItem(ctx, listID, &models.ReadingListItem{ID: itemID})
+}
+
+// RemoveArticleFromReadingList deletes the item holding an article from a list
+func RemoveArticleFromReadingList(ctx *gin.Context, listID, articleID string) error {
+	return removeReadingListItem(ctx, listID, &models.ReadingListItem{ArticleID: articleID})
+}
+
+// removeReadingListItem deletes the item matching cond from a list and closes
+// the gap it leaves in the positions
+func removeReadingListItem(ctx *gin.Context, listID string, cond *models.ReadingListItem) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockReadingList(tx, listID); err != nil {
+			return err
+		}
+
+		var item models.ReadingListItem
+		if err := tx.Where("list_id = ?", listID).Where(cond).First(&item).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("item not found")
+			}
+			return err
+		}
+
+		if err := tx.Delete(&item).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.ReadingListItem{}).
+			Where("list_id = ? AND position > ?", listID, item.Position).
+			Update("position", gorm.Expr("position - 1")).Error
+	})
+}
+
+// ReorderReadingListItems sets item positions to follow the given order,
+// which must name every item in the list exactly once
+func ReorderReadingListItems(ctx *gin.Context, listID string, itemIDs []string) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockReadingList(tx, listID); err != nil {
+			return err
+		}
+
+		var current []string
+		if err := tx.Model(&models.ReadingListItem{}).
+			Where("list_id = ?", listID).
+			Pluck("id", &current).Error; err != nil {
+			return err
+		}
+		if !isPermutation(current, itemIDs) {
+			return ErrInvalidOrder
+		}
+
+		for i, id := range itemIDs {
+			if err := tx.Model(&models.ReadingListItem{}).
+				Where("id = ?", id).
+				Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// isPermutation reports whether b contains exactly the elements of a
+func isPermutation(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]int, len(a))
+	for _, id := range a {
+		seen[id]++
+	}
+	for _, id := range b {
+		if seen[id] == 0 {
+			return false
+		}
+		seen[id]--
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"Praiseson6065/ocrolus-be/config"
+	"Praiseson6065/ocrolus-be/models"
+	"Praiseson6065/ocrolus-be/util"
+	"log"
+)
+
+// excerptLength is the maximum number of characters in a generated excerpt
+const excerptLength = 300
+
+// renderArticleContent refreshes the cached, sanitized HTML of an article
+// from its source content. For block documents the plain-text Content is
+// derived from the blocks as well, so search keeps working on it.
+func renderArticleContent(article *models.Article) {
+	switch article.Format {
+	case models.ContentFormatBlocks:
+		article.Content = util.BlocksPlaintext(article.Blocks)
+		article.ContentHTML = util.SanitizeHTML(util.RenderBlocksHTML(article.Blocks))
+	case models.ContentFormatMarkdown:
+		article.ContentHTML = util.SanitizeHTML(util.RenderMarkdown(article.Content))
+	case models.ContentFormatHTML:
+		article.ContentHTML = util.SanitizeHTML(article.Content)
+	default:
+		article.ContentHTML = util.RenderPlaintext(article.Content)
+	}
+	describeArticleContent(article)
+}
+
+// describeArticleContent derives the word count, reading time, excerpt and
+// summary of an article from its rendered HTML. The excerpt is the author's
+// own if they wrote one, otherwise the opening paragraph.
+func describeArticleContent(article *models.Article) {
+	blocks := util.HTMLTextBlocks(article.ContentHTML)
+
+	article.WordCount = util.WordCount(util.HTMLToText(article.ContentHTML))
+	article.ReadingTime = util.ReadingTime(article.WordCount)
+
+	article.Excerpt = article.CustomExcerpt
+	if article.Excerpt == "" && len(blocks) > 0 {
+		opening := blocks[0].Text
+		for _, block := range blocks {
+			if !block.Heading {
+				opening = block.Text
+				break
+			}
+		}
+		article.Excerpt = util.Excerpt(opening, excerptLength)
+	}
+
+	var paragraphs []string
+	for _, block := range blocks {
+		if !block.Heading {
+			paragraphs = append(paragraphs, block.Text)
+		}
+	}
+	article.Summary = util.Summarize(paragraphs, config.Config.Summaries.Sentences)
+}
+
+// articleDescriptionValues are the column values set by describeArticleContent
+func articleDescriptionValues(article *models.Article) map[string]interface{} {
+	return map[string]interface{}{
+		"excerpt":      article.Excerpt,
+		"summary":      article.Summary,
+		"word_count":   article.WordCount,
+		"reading_time": article.ReadingTime,
+	}
+}
+
+// migrateArticleHTML renders articles whose HTML has not been cached yet
+func migrateArticleHTML() error {
+	var articles []models.Article
+	if err := db.Unscoped().Where("content_html IS NULL").Find(&articles).Error; err != nil {
+		return err
+	}
+
+	for i := range articles {
+		renderArticleContent(&articles[i])
+		if err := db.Unscoped().Model(&articles[i]).
+			Update("content_html", articles[i].ContentHTML).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(articles) > 0 {
+		log.Printf("Rendered HTML for %d articles", len(articles))
+	}
+	return nil
+}
+
+// migrateArticleDescriptions fills in the excerpt, summary and reading
+// statistics of articles created before they were stored
+func migrateArticleDescriptions() error {
+	var articles []models.Article
+	if err := db.Unscoped().Where("excerpt IS NULL").Find(&articles).Error; err != nil {
+		return err
+	}
+
+	for i := range articles {
+		describeArticleContent(&articles[i])
+		if err := db.Unscoped().Model(&articles[i]).
+			Updates(articleDescriptionValues(&articles[i])).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(articles) > 0 {
+		log.Printf("Described %d articles", len(articles))
+	}
+	return nil
+}
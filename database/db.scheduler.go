@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"log"
+
+	"Praiseson6065/ocrolus-be/models"
+
+	"gorm.io/gorm"
+)
+
+// Advisory lock keys used by background jobs. Each job takes its own key so
+// that only one replica runs it at a time.
+const (
+	LockArticleSchedule int64 = 26001
+	LockTrashPurge      int64 = 26002
+)
+
+// WithAdvisoryLock runs fn inside a transaction holding the given Postgres
+// transaction-level advisory lock. If another session already holds the lock
+// fn is skipped and false is returned.
+func WithAdvisoryLock(ctx context.Context, key int64, fn func(tx *gorm.DB) error) (bool, error) {
+	acquired := false
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", key).Scan(&acquired).Error; err != nil {
+			return err
+		}
+		if !acquired {
+			return nil
+		}
+		return fn(tx)
+	})
+	return acquired, err
+}
+
+// ApplyArticleSchedules publishes approved articles whose publish time has
+// passed and archives published articles whose unpublish time has passed.
+// Schedules are cleared once applied so that a later manual change is not
+// overridden. Anything missed during downtime is picked up on the next run
+// because due times are compared with <=.
+func ApplyArticleSchedules(ctx context.Context) error {
+	_, err := WithAdvisoryLock(ctx, LockArticleSchedule, func(tx *gorm.DB) error {
+		now := db.NowFunc()
+
+		// Unpublish first so that an article whose whole window elapsed
+		// during downtime does not get published.
+		unpublished, err := applyScheduledTransition(tx,
+			models.ArticleStatePublished, models.ArticleStateArchived,
+			map[string]interface{}{"unpublish_at": nil},
+			"unpublish_at IS NOT NULL AND unpublish_at <= ?", now,
+		)
+		if err != nil {
+			return err
+		}
+
+		// Approved articles whose window already closed are never published
+		if err := tx.Model(&models.Article{}).
+			Where("state <> ? AND unpublish_at IS NOT NULL AND unpublish_at <= ?", models.ArticleStatePublished, now).
+			Updates(map[string]interface{}{"publish_at": nil, "unpublish_at": nil, "version": nextVersion}).Error; err != nil {
+			return err
+		}
+
+		published, err := applyScheduledTransition(tx,
+			models.ArticleStateApproved, models.ArticleStatePublished,
+			map[string]interface{}{"publish_at": nil},
+			"publish_at IS NOT NULL AND publish_at <= ?", now,
+		)
+		if err != nil {
+			return err
+		}
+
+		if unpublished > 0 || published > 0 {
+			log.Printf("Scheduler: published %d, unpublished %d articles", published, unpublished)
+		}
+		return nil
+	})
+	return err
+}
+
+// applyScheduledTransition moves every article in fromState matching cond to
+// toState and records a transition without an actor for each of them
+func applyScheduledTransition(tx *gorm.DB, fromState, toState string, updates map[string]interface{}, cond string, args ...interface{}) (int, error) {
+	var ids []string
+	if err := tx.Model(&models.Article{}).
+		Where("state = ?", fromState).
+		Where(cond, args...).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	updates["state"] = toState
+	updates["version"] = nextVersion
+	if err := tx.Model(&models.Article{}).
+		Where("id IN ? AND state = ?", ids, fromState).
+		Updates(updates).Error; err != nil {
+		return 0, err
+	}
+
+	transitions := make([]models.ArticleTransition, len(ids))
+	for i, id := range ids {
+		transitions[i] = models.ArticleTransition{
+			ArticleID: id,
+			FromState: fromState,
+			ToState:   toState,
+			Comment:   "Applied by scheduler",
+		}
+	}
+	if err := tx.Create(&transitions).Error; err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
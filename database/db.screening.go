@@ -0,0 +1,349 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+
+	"Praiseson6065/ocrolus-be/config"
+	"Praiseson6065/ocrolus-be/models"
+	"Praiseson6065/ocrolus-be/screening"
+	"Praiseson6065/ocrolus-be/util"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrScreeningReviewed is returned when a held submission that has already
+// been reviewed is reviewed again
+var ErrScreeningReviewed = errors.New("submission has already been reviewed")
+
+// ScreeningRejectedError is returned when content screening rejects an
+// article submission. The rejection has been recorded in Screening.
+type ScreeningRejectedError struct {
+	Screening *models.ArticleScreening
+}
+
+func (e *ScreeningRejectedError) Error() string {
+	return "article rejected by content screening"
+}
+
+var (
+	screeningPipeline     *screening.Pipeline
+	screeningPipelineOnce sync.Once
+)
+
+// articleScreening returns the pipeline article submissions go through,
+// building the built-in checks from the configuration on first use
+func articleScreening() *screening.Pipeline {
+	screeningPipelineOnce.Do(func() {
+		cfg := config.Config.Screening
+
+		terms := append([]string{}, screening.DefaultBlocklist...)
+		if cfg.Blocklist != "" {
+			terms = append(terms, strings.Split(cfg.Blocklist, ",")...)
+		}
+		if cfg.BlocklistFile != "" {
+			fileTerms, err := screening.LoadBlocklistFile(cfg.BlocklistFile)
+			if err != nil {
+				log.Printf("Screening: could not load blocklist %s: %v", cfg.BlocklistFile, err)
+			}
+			terms = append(terms, fileTerms...)
+		}
+
+		screeningPipeline = screening.NewPipeline(
+			screening.NewBlocklist(terms, cfg.BlocklistReject),
+			screening.NewLinkDensity(cfg.MaxLinks),
+			screening.NewRepetition(cfg.MaxRepetition),
+			screening.NewNaiveBayes(spamModel{}, cfg.SpamHold, cfg.SpamReject),
+			screening.NewDuplicate(similarityIndex{}, cfg.MaxSimilarity),
+		)
+	})
+	return screeningPipeline
+}
+
+// AddScreeningCheck plugs an extra check into the article screening
+// pipeline. It must be called before the server starts handling requests.
+func AddScreeningCheck(check screening.Check) {
+	articleScreening().Add(check)
+}
+
+// screeningContent is the part of an article content screening looks at
+func screeningContent(article *models.Article) *screening.Content {
+	return &screening.Content{
+		ArticleID: article.ID,
+		AuthorID:  article.AuthorID,
+		Title:     article.Title,
+		Text:      util.HTMLToText(article.ContentHTML),
+		HTML:      article.ContentHTML,
+	}
+}
+
+// screenArticle runs a rendered article through content screening. It
+// returns nil when screening is off or the author is trusted: editors,
+// moderators and admins are not screened.
+func screenArticle(ctx *gin.Context, article *models.Article) *models.ArticleScreening {
+	if !config.Config.Screening.Enabled {
+		return nil
+	}
+	author, err := GetUserByID(ctx, article.AuthorID)
+	if err == nil && (author.IsEditor() || author.IsModerator()) {
+		return nil
+	}
+
+	content := screeningContent(article)
+	result := articleScreening().Run(ctx, content)
+	record := &models.ArticleScreening{
+		AuthorID: article.AuthorID,
+		Title:    article.Title,
+		Text:     content.Text,
+		Verdict:  result.Verdict,
+		Reasons:  result.Reasons,
+	}
+	if article.ID != "" {
+		record.ArticleID = &article.ID
+	}
+	if result.Verdict == models.ScreeningHold {
+		record.ReviewStatus = models.ScreeningReviewPending
+	}
+	return record
+}
+
+// rejectScreenedArticle records a rejected submission and returns the error
+// reporting it
+func rejectScreenedArticle(ctx *gin.Context, record *models.ArticleScreening) error {
+	if err := db.WithContext(ctx).Create(record).Error; err != nil {
+		return err
+	}
+	return &ScreeningRejectedError{Screening: record}
+}
+
+// GetScreeningByID returns a screening record with its author and reviewer
+func GetScreeningByID(ctx *gin.Context, id string) (*models.ArticleScreening, error) {
+	var record models.ArticleScreening
+	result := db.WithContext(ctx).Preload("Author").Preload("ReviewedBy").Where("id = ?", id).First(&record)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("screening not found")
+		}
+		return nil, result.Error
+	}
+	return &record, nil
+}
+
+// ScreeningListOptions narrows the records returned by ListScreenings
+type ScreeningListOptions struct {
+	Verdict      string
+	ReviewStatus string
+	AuthorID     string
+	ArticleID    string
+}
+
+// ListScreenings returns a page of screening records, newest first
+func ListScreenings(ctx *gin.Context, page, pageSize int, opts ScreeningListOptions) ([]models.ArticleScreening, int64, error) {
+	var records []models.ArticleScreening
+	var count int64
+	query := db.WithContext(ctx).Model(&models.ArticleScreening{})
+
+	if opts.Verdict != "" {
+		query = query.Where("verdict = ?", opts.Verdict)
+	}
+	if opts.ReviewStatus != "" {
+		query = query.Where("review_status = ?", opts.ReviewStatus)
+	}
+	if opts.AuthorID != "" {
+		query = query.Where("author_id = ?", opts.AuthorID)
+	}
+	if opts.ArticleID != "" {
+		query = query.Where("article_id = ?", opts.ArticleID)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	result := query.Preload("Author").Preload("ReviewedBy").
+		Offset(offset).Limit(pageSize).Order("created_at DESC, id DESC").Find(&records)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return records, count, nil
+}
+
+// ReviewScreening settles a held submission. Approving it lifts the hold,
+// so the article stays hidden only if reports hid it as well; rejecting it
+// moves the article to the trash, where it stays hidden. Every pending hold on the same article is settled with it, the
+// classifier learns from the decision and the decision is logged.
+func ReviewScreening(ctx *gin.Context, record *models.ArticleScreening, approve bool, actorID, note string) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		status, action, class := models.ScreeningReviewApproved, models.ModerationApproveHeld, models.SpamClassHam
+		if !approve {
+			status, action, class = models.ScreeningReviewRejected, models.ModerationRejectHeld, models.SpamClassSpam
+		}
+
+		result := tx.Model(&models.ArticleScreening{}).
+			Where("id = ? AND review_status = ?", record.ID, models.ScreeningReviewPending).
+			Updates(map[string]interface{}{
+				"review_status":  status,
+				"reviewed_by_id": actorID,
+				"reviewed_at":    db.NowFunc(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrScreeningReviewed
+		}
+		if record.ArticleID == nil {
+			return nil
+		}
+		articleID := *record.ArticleID
+
+		if err := tx.Model(&models.ArticleScreening{}).
+			Where("article_id = ? AND review_status = ?", articleID, models.ScreeningReviewPending).
+			Updates(map[string]interface{}{
+				"review_status":  status,
+				"reviewed_by_id": actorID,
+				"reviewed_at":    db.NowFunc(),
+			}).Error; err != nil {
+			return err
+		}
+
+		var article models.Article
+		if err := tx.Unscoped().Where("id = ?", articleID).First(&article).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		// Learn from what was held, not from later edits. Older records
+		// did not keep their text.
+		if record.Text != "" {
+			held := &screening.Content{Title: record.Title, Text: record.Text}
+			if err := trainSpamFilter(tx, articleID, held, class); err != nil {
+				return err
+			}
+		}
+
+		if approve {
+			if err := tx.Unscoped().Model(&models.Article{}).
+				Where("id = ?", articleID).
+				Updates(map[string]interface{}{"held_at": nil, "version": nextVersion}).Error; err != nil {
+				return err
+			}
+		} else {
+			if err := tx.Where("id = ?", articleID).Delete(&models.Article{}).Error; err != nil {
+				return err
+			}
+			if err := unindexArticles(tx, []string{articleID}); err != nil {
+				return err
+			}
+		}
+
+		return tx.Create(&models.ModerationAction{
+			ActorID:      &actorID,
+			Action:       action,
+			TargetType:   models.ReportTargetArticle,
+			TargetID:     articleID,
+			TargetUserID: article.AuthorID,
+			Note:         note,
+		}).Error
+	})
+}
+
+// migrateScreeningHolds moves holds by content screening from hidden_at to
+// held_at. Articles with a pending hold and no open report were hidden by
+// screening alone.
+func migrateScreeningHolds() error {
+	log.Println("Migrating screening holds...")
+	return db.Exec(`UPDATE articles SET held_at = hidden_at, hidden_at = NULL
+		WHERE hidden_at IS NOT NULL
+		AND EXISTS (SELECT 1 FROM article_screenings s WHERE s.article_id = articles.id AND s.review_status = ?)
+		AND NOT EXISTS (SELECT 1 FROM content_reports r WHERE r.target_type = ? AND r.target_id = articles.id AND r.status IN ?)`,
+		models.ScreeningReviewPending, models.ReportTargetArticle, openReportStatuses).Error
+}
+
+// trainSpamFilter teaches the spam classifier that the content of an article
+// belongs to the given class. Each article is learnt from once, on the first
+// decision about it, so that a held article that is later reported does not
+// count twice.
+func trainSpamFilter(tx *gorm.DB, articleID string, content *screening.Content, class string) error {
+	result := tx.Unscoped().Model(&models.Article{}).
+		Where("id = ? AND spam_trained_at IS NULL", articleID).
+		UpdateColumn("spam_trained_at", db.NowFunc())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	tokens := screening.Tokens(content)
+
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "class"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"documents": gorm.Expr("spam_classes.documents + 1")}),
+	}).Create(&models.SpamClass{Class: class, Documents: 1}).Error; err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	column := "ham_count"
+	if class == models.SpamClassSpam {
+		column = "spam_count"
+	}
+	rows := make([]models.SpamToken, len(tokens))
+	for i, token := range tokens {
+		rows[i] = models.SpamToken{Token: token}
+		if class == models.SpamClassSpam {
+			rows[i].SpamCount = 1
+		} else {
+			rows[i].HamCount = 1
+		}
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "token"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{column: gorm.Expr("spam_tokens." + column + " + 1")}),
+	}).Create(&rows).Error
+}
+
+// spamModel reads the classifier's training data from the database
+type spamModel struct{}
+
+func (spamModel) Documents(ctx context.Context) (int64, int64, error) {
+	var classes []models.SpamClass
+	if err := db.WithContext(ctx).Find(&classes).Error; err != nil {
+		return 0, 0, err
+	}
+
+	var spam, ham int64
+	for _, class := range classes {
+		switch class.Class {
+		case models.SpamClassSpam:
+			spam = class.Documents
+		case models.SpamClassHam:
+			ham = class.Documents
+		}
+	}
+	return spam, ham, nil
+}
+
+func (spamModel) TokenCounts(ctx context.Context, tokens []string) (map[string]screening.TokenCount, error) {
+	var rows []models.SpamToken
+	if err := db.WithContext(ctx).Where("token IN ?", tokens).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]screening.TokenCount, len(rows))
+	for _, row := range rows {
+		counts[row.Token] = screening.TokenCount{Spam: row.SpamCount, Ham: row.HamCount}
+	}
+	return counts, nil
+}
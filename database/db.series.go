@@ -0,0 +1,202 @@
+package database
+
+import (
+	"Praiseson6065/ocrolus-be/models"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrArticleInSeries is returned when an article already belongs to a series
+var ErrArticleInSeries = errors.New("article already belongs to a series")
+
+func CreateSeries(ctx *gin.Context, series *models.Series) (*models.Series, error) {
+	if err := db.WithContext(ctx).Create(series).Error; err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// GetSeriesByID returns a series with its parts in order
+func GetSeriesByID(ctx *gin.Context, id string) (*models.Series, error) {
+	var series models.Series
+	result := db.WithContext(ctx).
+		Preload("Author").
+		Preload("Parts", func(tx *gorm.DB) *gorm.DB {
+			return tx.Order("position ASC")
+		}).
+		Preload("Parts.Article").
+		Where("id = ?", id).
+		First(&series)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("series not found")
+		}
+		return nil, result.Error
+	}
+	return &series, nil
+}
+
+// GetSeriesForArticle returns the series an article belongs to, or nil if it
+// is not part of one
+func GetSeriesForArticle(ctx *gin.Context, articleID string) (*models.Series, error) {
+	var part models.SeriesPart
+	err := db.WithContext(ctx).Where("article_id = ?", articleID).First(&part).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	series, err := GetSeriesByID(ctx, part.SeriesID)
+	if err != nil {
+		// A part can outlive its soft-deleted series
+		return nil, nil
+	}
+	return series, nil
+}
+
+// ListSeries returns an author's series with their part counts
+func ListSeries(ctx *gin.Context, authorID string) ([]models.Series, error) {
+	var series []models.Series
+	err := db.WithContext(ctx).
+		Select("series.*, (SELECT COUNT(*) FROM series_parts WHERE series_parts.series_id = series.id) AS part_count").
+		Where("author_id = ?", authorID).
+		Order("created_at ASC").
+		Find(&series).Error
+	if err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func UpdateSeries(ctx *gin.Context, series *models.Series) error {
+	return db.WithContext(ctx).Model(&models.Series{}).
+		Where("id = ?", series.ID).
+		Updates(map[string]interface{}{
+			"title":       series.Title,
+			"description": series.Description,
+		}).Error
+}
+
+// DeleteSeries removes a series. Its articles are kept and become standalone.
+func DeleteSeries(ctx *gin.Context, id string) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ?", id).Delete(&models.SeriesPart{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", id).Delete(&models.Series{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("series not found")
+		}
+		return nil
+	})
+}
+
+// lockSeries takes a row lock on a series so that position changes to its
+// parts are serialized
+func lockSeries(tx *gorm.DB, seriesID string) error {
+	var series models.Series
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", seriesID).
+		First(&series).Error
+}
+
+// AddSeriesPart appends an article to the end of a series
+func AddSeriesPart(ctx *gin.Context, seriesID, articleID string) (*models.SeriesPart, error) {
+	part := &models.SeriesPart{
+		SeriesID:  seriesID,
+		ArticleID: articleID,
+	}
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockSeries(tx, seriesID); err != nil {
+			return err
+		}
+
+		var existing int64
+		if err := tx.Model(&models.SeriesPart{}).
+			Where("article_id = ?", articleID).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return ErrArticleInSeries
+		}
+
+		var last int
+		if err := tx.Model(&models.SeriesPart{}).
+			Where("series_id = ?", seriesID).
+			Select("COALESCE(MAX(position), 0)").
+			Scan(&last).Error; err != nil {
+			return err
+		}
+
+		part.Position = last + 1
+		return tx.Create(part).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return part, nil
+}
+
+// RemoveSeriesPart takes an article out of a series and closes the gap it
+// leaves in the positions
+func RemoveSeriesPart(ctx *gin.Context, seriesID, articleID string) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockSeries(tx, seriesID); err != nil {
+			return err
+		}
+
+		var part models.SeriesPart
+		if err := tx.Where("series_id = ? AND article_id = ?", seriesID, articleID).First(&part).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("part not found")
+			}
+			return err
+		}
+
+		if err := tx.Delete(&part).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.SeriesPart{}).
+			Where("series_id = ? AND position > ?", seriesID, part.Position).
+			Update("position", gorm.Expr("position - 1")).Error
+	})
+}
+
+// ReorderSeriesParts sets part positions to follow the given article order,
+// which must name every article in the series exactly once
+func ReorderSeriesParts(ctx *gin.Context, seriesID string, articleIDs []string) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockSeries(tx, seriesID); err != nil {
+			return err
+		}
+
+		var current []string
+		if err := tx.Model(&models.SeriesPart{}).
+			Where("series_id = ?", seriesID).
+			Pluck("article_id", &current).Error; err != nil {
+			return err
+		}
+		if !isPermutation(current, articleIDs) {
+			return ErrInvalidOrder
+		}
+
+		for i, articleID := range articleIDs {
+			if err := tx.Model(&models.SeriesPart{}).
+				Where("series_id = ? AND article_id = ?", seriesID, articleID).
+				Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,216 @@
+package database
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"Praiseson6065/ocrolus-be/models"
+	"Praiseson6065/ocrolus-be/screening"
+	"Praiseson6065/ocrolus-be/util"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SimilarArticle is an article whose content resembles another one, with
+// the estimated share of word shingles they have in common
+type SimilarArticle struct {
+	Article    models.Article
+	Similarity float64
+}
+
+// articleSignature computes the MinHash signature of an article's rendered
+// text, so that markup and formatting do not affect the comparison
+func articleSignature(article *models.Article) []uint32 {
+	return util.MinHash(util.HTMLToText(article.ContentHTML))
+}
+
+// saveArticleSignature stores the signature and LSH buckets of an article's
+// content, replacing the previous ones. Articles without text have none.
+func saveArticleSignature(tx *gorm.DB, article *models.Article) error {
+	if err := tx.Where("article_id = ?", article.ID).Delete(&models.ArticleSignatureBucket{}).Error; err != nil {
+		return err
+	}
+
+	signature := articleSignature(article)
+	if signature == nil {
+		return tx.Where("article_id = ?", article.ID).Delete(&models.ArticleSignature{}).Error
+	}
+
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "article_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"signature", "updated_at"}),
+	}).Create(&models.ArticleSignature{
+		ArticleID: article.ID,
+		Signature: signature,
+	}).Error; err != nil {
+		return err
+	}
+
+	buckets := util.LSHBuckets(signature)
+	rows := make([]models.ArticleSignatureBucket, len(buckets))
+	for band, bucket := range buckets {
+		rows[band] = models.ArticleSignatureBucket{ArticleID: article.ID, Band: band, Bucket: bucket}
+	}
+	return tx.Create(&rows).Error
+}
+
+// similarityOptions narrows the articles returned by findSimilar
+type similarityOptions struct {
+	ExcludeArticleID string
+	ExcludeAuthorID  string
+	MinSimilarity    float64
+	Limit            int
+}
+
+// findSimilar returns live articles whose content resembles the given
+// signature, most similar first. Candidates are the articles sharing an LSH
+// bucket with the signature; their similarity is then estimated from the
+// full signatures.
+func findSimilar(ctx context.Context, signature []uint32, opts similarityOptions) ([]SimilarArticle, error) {
+	buckets := util.LSHBuckets(signature)
+	if len(buckets) == 0 {
+		return nil, nil
+	}
+	bands := make([][]interface{}, len(buckets))
+	for band, bucket := range buckets {
+		bands[band] = []interface{}{band, bucket}
+	}
+
+	candidates := db.WithContext(ctx).Model(&models.ArticleSignatureBucket{}).
+		Select("DISTINCT article_id").
+		Where("(band, bucket) IN ?", bands)
+	if opts.ExcludeArticleID != "" {
+		candidates = candidates.Where("article_id <> ?", opts.ExcludeArticleID)
+	}
+
+	query := db.WithContext(ctx).Model(&models.ArticleSignature{}).
+		Joins("JOIN articles ON articles.id = article_signatures.article_id AND articles.deleted_at IS NULL").
+		Where("article_signatures.article_id IN (?)", candidates)
+	if opts.ExcludeAuthorID != "" {
+		query = query.Where("articles.author_id <> ?", opts.ExcludeAuthorID)
+	}
+
+	var signatures []models.ArticleSignature
+	if err := query.Select("article_signatures.*").Find(&signatures).Error; err != nil {
+		return nil, err
+	}
+
+	var similar []SimilarArticle
+	for _, candidate := range signatures {
+		similarity := util.MinHashSimilarity(signature, candidate.Signature)
+		if similarity >= opts.MinSimilarity && similarity > 0 {
+			similar = append(similar, SimilarArticle{
+				Article:    models.Article{ID: candidate.ArticleID},
+				Similarity: similarity,
+			})
+		}
+	}
+	sort.SliceStable(similar, func(i, j int) bool {
+		if similar[i].Similarity != similar[j].Similarity {
+			return similar[i].Similarity > similar[j].Similarity
+		}
+		return similar[i].Article.ID < similar[j].Article.ID
+	})
+	if opts.Limit > 0 && len(similar) > opts.Limit {
+		similar = similar[:opts.Limit]
+	}
+	return similar, nil
+}
+
+// FindSimilarArticles returns the live articles whose content resembles the
+// given article's, most similar first
+func FindSimilarArticles(ctx *gin.Context, article *models.Article, minSimilarity float64, limit int) ([]SimilarArticle, error) {
+	var stored models.ArticleSignature
+	err := db.WithContext(ctx).Where("article_id = ?", article.ID).Limit(1).Find(&stored).Error
+	if err != nil {
+		return nil, err
+	}
+	signature := []uint32(stored.Signature)
+	if stored.ArticleID == "" {
+		signature = articleSignature(article)
+	}
+
+	similar, err := findSimilar(ctx, signature, similarityOptions{
+		ExcludeArticleID: article.ID,
+		MinSimilarity:    minSimilarity,
+		Limit:            limit,
+	})
+	if err != nil || len(similar) == 0 {
+		return similar, err
+	}
+
+	ids := make([]string, len(similar))
+	for i := range similar {
+		ids[i] = similar[i].Article.ID
+	}
+	var articles []models.Article
+	if err := db.WithContext(ctx).Scopes(preloadArticle).Where("id IN ?", ids).Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[string]models.Article, len(articles))
+	for _, loaded := range articles {
+		byID[loaded.ID] = loaded
+	}
+
+	result := similar[:0]
+	for _, match := range similar {
+		if loaded, ok := byID[match.Article.ID]; ok {
+			match.Article = loaded
+			result = append(result, match)
+		}
+	}
+	return result, nil
+}
+
+// similarityIndex lets content screening compare submissions with the
+// stored signatures
+type similarityIndex struct{}
+
+func (similarityIndex) MostSimilar(ctx context.Context, content *screening.Content) (string, float64, error) {
+	signature := util.MinHash(content.Text)
+	if signature == nil {
+		return "", 0, nil
+	}
+
+	similar, err := findSimilar(ctx, signature, similarityOptions{
+		ExcludeArticleID: content.ArticleID,
+		ExcludeAuthorID:  content.AuthorID,
+		Limit:            1,
+	})
+	if err != nil || len(similar) == 0 {
+		return "", 0, err
+	}
+	return similar[0].Article.ID, similar[0].Similarity, nil
+}
+
+// migrateArticleSignatures computes the signatures of articles saved before
+// they were stored
+func migrateArticleSignatures() error {
+	var articles []models.Article
+	if err := db.Unscoped().
+		Where("id NOT IN (?)", db.Model(&models.ArticleSignature{}).Select("article_id")).
+		Find(&articles).Error; err != nil {
+		return err
+	}
+
+	count := 0
+	for i := range articles {
+		if articleSignature(&articles[i]) == nil {
+			continue
+		}
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			return saveArticleSignature(tx, &articles[i])
+		}); err != nil {
+			return err
+		}
+		count++
+	}
+
+	if count > 0 {
+		log.Printf("Computed signatures for %d articles", count)
+	}
+	return nil
+}
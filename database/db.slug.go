@@ -0,0 +1,149 @@
+package database
+
+import (
+	"Praiseson6065/ocrolus-be/models"
+	"Praiseson6065/ocrolus-be/util"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrSlugTaken is returned when an explicitly requested slug belongs to another article
+var ErrSlugTaken = errors.New("slug is already in use")
+
+// assignSlug sets the slug of an article. An explicit slug must not belong to
+// another article. When slug is empty one is derived from the title, adding a
+// numeric suffix if the plain form is already taken.
+func assignSlug(tx *gorm.DB, article *models.Article, slug string) error {
+	if slug != "" {
+		var owners []string
+		if err := tx.Model(&models.ArticleSlug{}).
+			Where("slug = ? AND article_id <> ?", slug, article.ID).
+			Pluck("article_id", &owners).Error; err != nil {
+			return err
+		}
+		if len(owners) > 0 {
+			return ErrSlugTaken
+		}
+		article.Slug = slug
+		return nil
+	}
+
+	base := util.Slugify(article.Title)
+	if base == "" {
+		base = "article"
+	}
+
+	// Slugs only contain letters, digits and hyphens, so they are safe in LIKE
+	var taken []string
+	if err := tx.Model(&models.ArticleSlug{}).
+		Where("(slug = ? OR slug LIKE ?) AND article_id <> ?", base, base+"-%", article.ID).
+		Pluck("slug", &taken).Error; err != nil {
+		return err
+	}
+
+	takenSet := make(map[string]bool, len(taken))
+	for _, s := range taken {
+		takenSet[s] = true
+	}
+
+	candidate := base
+	for n := 2; takenSet[candidate]; n++ {
+		candidate = fmt.Sprintf("%s-%d", base, n)
+	}
+	article.Slug = candidate
+	return nil
+}
+
+// maxSlugAttempts bounds how often a derived slug is chosen again when other
+// articles keep taking it concurrently
+const maxSlugAttempts = 5
+
+// articleSlugIndex is the unique index on the current slugs of articles
+const articleSlugIndex = "idx_articles_slug"
+
+// isSlugConflict reports whether err violates the unique index on article
+// slugs
+func isSlugConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == articleSlugIndex
+}
+
+// saveWithSlug assigns a slug like assignSlug and runs save, which writes the
+// article with it, in a savepoint. If another article takes a derived slug in
+// the meantime the next free one is tried; an explicit slug that is taken
+// this way fails with ErrSlugTaken.
+func saveWithSlug(tx *gorm.DB, article *models.Article, slug string, save func(tx *gorm.DB) error) error {
+	for attempt := 1; ; attempt++ {
+		if err := assignSlug(tx, article, slug); err != nil {
+			return err
+		}
+		err := tx.Transaction(save)
+		if !isSlugConflict(err) {
+			return err
+		}
+		if slug != "" || attempt == maxSlugAttempts {
+			return ErrSlugTaken
+		}
+	}
+}
+
+// recordSlug adds the article's current slug to its slug history
+func recordSlug(tx *gorm.DB, article *models.Article) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "slug"}},
+		DoNothing: true,
+	}).Create(&models.ArticleSlug{
+		Slug:      article.Slug,
+		ArticleID: article.ID,
+	}).Error
+}
+
+// GetArticleBySlug finds an article by its current or any previous slug.
+// Callers can compare the returned article's Slug with the requested one to
+// detect a historical slug.
+func GetArticleBySlug(ctx *gin.Context, slug string) (*models.Article, error) {
+	var history models.ArticleSlug
+	result := db.WithContext(ctx).Where("slug = ?", slug).First(&history)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("article not found")
+		}
+		return nil, result.Error
+	}
+	return GetArticleByID(ctx, history.ArticleID)
+}
+
+// migrateArticleSlugs generates slugs for articles created before slugs existed
+func migrateArticleSlugs() error {
+	var articles []models.Article
+	if err := db.Unscoped().Where("slug IS NULL OR slug = ''").Find(&articles).Error; err != nil {
+		return err
+	}
+
+	for i := range articles {
+		article := &articles[i]
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := assignSlug(tx, article, ""); err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Model(article).Update("slug", article.Slug).Error; err != nil {
+				return err
+			}
+			return recordSlug(tx, article)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(articles) > 0 {
+		log.Printf("Generated slugs for %d articles", len(articles))
+	}
+	return nil
+}
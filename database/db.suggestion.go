@@ -0,0 +1,121 @@
+package database
+
+import (
+	"Praiseson6065/ocrolus-be/models"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ErrSuggestionClosed is returned when a suggestion that has already been
+// accepted, rejected or withdrawn is reviewed again
+var ErrSuggestionClosed = errors.New("suggestion is no longer pending")
+
+func CreateSuggestion(ctx *gin.Context, suggestion *models.ArticleSuggestion) (*models.ArticleSuggestion, error) {
+	if err := db.WithContext(ctx).Create(suggestion).Error; err != nil {
+		return nil, err
+	}
+	return GetSuggestionByID(ctx, suggestion.ArticleID, suggestion.ID)
+}
+
+// GetSuggestionByID returns a suggestion on the given article
+func GetSuggestionByID(ctx *gin.Context, articleID, id string) (*models.ArticleSuggestion, error) {
+	var suggestion models.ArticleSuggestion
+	result := db.WithContext(ctx).
+		Preload("Author").
+		Preload("ReviewedBy").
+		Where("id = ? AND article_id = ?", id, articleID).
+		First(&suggestion)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("suggestion not found")
+		}
+		return nil, result.Error
+	}
+	return &suggestion, nil
+}
+
+// SuggestionListOptions narrows the suggestions returned by ListSuggestions
+type SuggestionListOptions struct {
+	AuthorID string
+	Status   string
+}
+
+// ListSuggestions returns a page of suggestions on an article, newest first
+func ListSuggestions(ctx *gin.Context, articleID string, page, pageSize int, opts SuggestionListOptions) ([]models.ArticleSuggestion, int64, error) {
+	var suggestions []models.ArticleSuggestion
+	var count int64
+	query := db.WithContext(ctx).Model(&models.ArticleSuggestion{}).Where("article_id = ?", articleID)
+
+	if opts.AuthorID != "" {
+		query = query.Where("author_id = ?", opts.AuthorID)
+	}
+	if opts.Status != "" {
+		query = query.Where("status = ?", opts.Status)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	result := query.Preload("Author").Preload("ReviewedBy").
+		Offset(offset).Limit(pageSize).Order("created_at DESC").Find(&suggestions)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return suggestions, count, nil
+}
+
+// CloseSuggestion moves a pending suggestion to its final status, recording
+// who closed it. Closing a suggestion that is no longer pending fails with
+// ErrSuggestionClosed.
+func CloseSuggestion(ctx *gin.Context, id, status, reviewerID, comment string) error {
+	return closeSuggestion(db.WithContext(ctx), id, status, reviewerID, comment)
+}
+
+// AcceptSuggestion saves an article edited with a suggestion's changes and
+// closes the suggestion as accepted in one transaction, so the edit is only
+// applied if the suggestion was still pending. A suggestion withdrawn or
+// reviewed in the meantime fails with ErrSuggestionClosed.
+func AcceptSuggestion(ctx *gin.Context, article *models.Article, id, reviewerID, comment string) (*models.Article, error) {
+	return updateArticleColumns(ctx, article, ArticleEditableColumns, func(tx *gorm.DB) error {
+		return closeSuggestion(tx, id, models.SuggestionAccepted, reviewerID, comment)
+	})
+}
+
+func closeSuggestion(tx *gorm.DB, id, status, reviewerID, comment string) error {
+	result := tx.Model(&models.ArticleSuggestion{}).
+		Where("id = ? AND status = ?", id, models.SuggestionPending).
+		Updates(map[string]interface{}{
+			"status":         status,
+			"reviewed_by_id": reviewerID,
+			"review_comment": comment,
+			"reviewed_at":    db.NowFunc(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSuggestionClosed
+	}
+	return nil
+}
+
+// ListSuggestionContributors returns the users whose suggestions have been
+// accepted into an article
+func ListSuggestionContributors(ctx *gin.Context, articleID string) ([]models.User, error) {
+	var users []models.User
+	err := db.WithContext(ctx).
+		Where("id IN (?)", db.Model(&models.ArticleSuggestion{}).
+			Select("author_id").
+			Where("article_id = ? AND status = ?", articleID, models.SuggestionAccepted)).
+		Order("name ASC").
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"Praiseson6065/ocrolus-be/models"
+	"Praiseson6065/ocrolus-be/util"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// setArticleTags replaces the tags of an article with the given names,
+// creating tags that do not exist yet. Names that reduce to the same slug
+// are treated as one tag.
+func setArticleTags(tx *gorm.DB, article *models.Article, names []string) error {
+	tags := make([]models.Tag, 0, len(names))
+	seen := map[string]bool{}
+	for _, name := range names {
+		slug := util.Slugify(name)
+		if slug == "" || seen[slug] {
+			continue
+		}
+		seen[slug] = true
+
+		tag := models.Tag{Name: name, Slug: slug}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&tag).Error; err != nil {
+			return err
+		}
+		// Another article may have created the tag first
+		if err := tx.Where("slug = ?", slug).First(&tag).Error; err != nil {
+			return err
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := tx.Model(article).Association("Tags").Replace(tags); err != nil {
+		return err
+	}
+	article.Tags = tags
+	return nil
+}
+
+// articleTagNames returns the names of the tags set on an article
+func articleTagNames(article *models.Article) []string {
+	names := make([]string, len(article.Tags))
+	for i, tag := range article.Tags {
+		names[i] = tag.Name
+	}
+	return names
+}
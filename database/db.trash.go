@@ -0,0 +1,216 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"Praiseson6065/ocrolus-be/config"
+	"Praiseson6065/ocrolus-be/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNotInTrash is returned when restoring or purging an article that has
+// not been deleted
+var ErrNotInTrash = errors.New("article is not in the trash")
+
+// trashPurgeBatch is the number of expired articles purged per scheduler run
+const trashPurgeBatch = 100
+
+// ListTrashedArticles returns a page of the deleted articles a user owns,
+// most recently deleted first
+func ListTrashedArticles(ctx *gin.Context, userID string, page, pageSize int) ([]models.Article, int64, error) {
+	var articles []models.Article
+	var count int64
+
+	query := db.WithContext(ctx).Unscoped().Model(&models.Article{}).
+		Where("deleted_at IS NOT NULL").
+		Where("author_id = ? OR id IN (?)", userID,
+			db.Model(&models.ArticleCollaborator{}).Select("article_id").
+				Where("user_id = ? AND role = ?", userID, models.CollaboratorOwner))
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	result := query.Scopes(preloadArticle).
+		Offset(offset).Limit(pageSize).Order("deleted_at DESC, id DESC").Find(&articles)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return articles, count, nil
+}
+
+// GetTrashedArticleByID returns a deleted article
+func GetTrashedArticleByID(ctx *gin.Context, id string) (*models.Article, error) {
+	var article models.Article
+	result := db.WithContext(ctx).Unscoped().Scopes(preloadArticle).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&article)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrNotInTrash
+		}
+		return nil, result.Error
+	}
+	return &article, nil
+}
+
+// RestoreArticle takes an article out of the trash. Everything attached to
+// it was kept while it was deleted, so it comes back as it was; its terms
+// rejoin the keyword corpus.
+func RestoreArticle(ctx *gin.Context, id string) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&models.Article{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Updates(map[string]interface{}{"deleted_at": nil, "version": nextVersion})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotInTrash
+		}
+
+		var article models.Article
+		if err := tx.Where("id = ?", id).First(&article).Error; err != nil {
+			return err
+		}
+		return saveArticleKeywords(tx, &article)
+	})
+}
+
+// PurgeArticle permanently removes an article in the trash together with
+// every row that refers to it
+func PurgeArticle(ctx *gin.Context, id string) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []string
+		if err := tx.Unscoped().Model(&models.Article{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return ErrNotInTrash
+		}
+		return purgeArticles(tx, ids)
+	})
+}
+
+// PurgeExpiredTrash permanently removes articles that have been in the trash
+// for longer than the retention window. Large backlogs are worked off in
+// batches over several runs.
+func PurgeExpiredTrash(ctx context.Context) error {
+	retention := config.Config.Trash.Retention
+	if retention <= 0 {
+		return nil
+	}
+
+	_, err := WithAdvisoryLock(ctx, LockTrashPurge, func(tx *gorm.DB) error {
+		var ids []string
+		if err := tx.Unscoped().Model(&models.Article{}).
+			Where("deleted_at IS NOT NULL AND deleted_at <= NOW() - make_interval(days => ?)", retention).
+			Order("deleted_at ASC").
+			Limit(trashPurgeBatch).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := purgeArticles(tx, ids); err != nil {
+			return err
+		}
+		log.Printf("Trash: purged %d articles", len(ids))
+		return nil
+	})
+	return err
+}
+
+// purgeArticles hard-deletes articles and their dependent rows. Series and
+// reading lists that lose an entry are locked and renumbered so their
+// positions stay contiguous.
+func purgeArticles(tx *gorm.DB, ids []string) error {
+	var seriesIDs, listIDs []string
+	if err := tx.Unscoped().Model(&models.Series{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN (?)", tx.Model(&models.SeriesPart{}).Select("series_id").Where("article_id IN ?", ids)).
+		Order("id").
+		Pluck("id", &seriesIDs).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Model(&models.ReadingList{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN (?)", tx.Model(&models.ReadingListItem{}).Select("list_id").Where("article_id IN ?", ids)).
+		Order("id").
+		Pluck("id", &listIDs).Error; err != nil {
+		return err
+	}
+
+	dependents := []interface{}{
+		&models.SeriesPart{},
+		&models.ReadingListItem{},
+		&models.RecentlyViewedArticle{},
+		&models.ArticleTransition{},
+		&models.ArticleSlug{},
+		&models.Comment{},
+		&models.ArticleReaction{},
+		&models.ArticleReactionCount{},
+		&models.ArticleCollaborator{},
+		&models.ArticleSuggestion{},
+		&models.ArticleEditLock{},
+		&models.ArticlePreviewLink{},
+		&models.ArticleSignature{},
+		&models.ArticleSignatureBucket{},
+		&models.ArticleScreening{},
+	}
+	if err := unindexArticles(tx, ids); err != nil {
+		return err
+	}
+	for _, dependent := range dependents {
+		if err := tx.Unscoped().Where("article_id IN ?", ids).Delete(dependent).Error; err != nil {
+			return err
+		}
+	}
+	if err := tx.Exec("DELETE FROM article_tags WHERE article_id IN ?", ids).Error; err != nil {
+		return err
+	}
+	// Reports go with the content; the moderation log keeps what was done
+	if err := tx.Where("target_type = ? AND target_id IN ?", models.ReportTargetArticle, ids).
+		Delete(&models.ContentReport{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("id IN ?", ids).Delete(&models.Article{}).Error; err != nil {
+		return err
+	}
+
+	if len(seriesIDs) > 0 {
+		if err := compactPositions(tx, "series_parts", "series_id", seriesIDs); err != nil {
+			return err
+		}
+	}
+	if len(listIDs) > 0 {
+		if err := compactPositions(tx, "reading_list_items", "list_id", listIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compactPositions renumbers the rows of table within each of the given
+// groups so that their positions run from 1 without gaps again
+func compactPositions(tx *gorm.DB, table, groupColumn string, groupIDs []string) error {
+	return tx.Exec(
+		"UPDATE "+table+" SET position = ranked.position FROM ("+
+			"SELECT id, ROW_NUMBER() OVER (PARTITION BY "+groupColumn+" ORDER BY position) AS position "+
+			"FROM "+table+" WHERE "+groupColumn+" IN ?"+
+			") AS ranked WHERE "+table+".id = ranked.id AND "+table+".position <> ranked.position",
+		groupIDs,
+	).Error
+}
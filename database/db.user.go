@@ -54,9 +54,31 @@ func GetPasswordByMail(ctx *gin.Context, email string) (string, string, error) {
 	return user.Password, user.ID, nil
 }
 
+// UserEditableColumns are the profile columns users can change themselves
+var UserEditableColumns = []string{"name", "email", "password"}
+
+// UpdateUser saves a user's profile fields if the user is still at
+// user.Version, and fails with ErrVersionConflict otherwise
 func UpdateUser(ctx *gin.Context, user *models.User) (models.User, error) {
+	return UpdateUserColumns(ctx, user, UserEditableColumns)
+}
+
+// UpdateUserColumns is UpdateUser restricted to the given editable columns
+func UpdateUserColumns(ctx *gin.Context, user *models.User, columns []string) (models.User, error) {
 	var updatedUser models.User
 
+	values := map[string]interface{}{"version": nextVersion}
+	for _, column := range columns {
+		switch column {
+		case "name":
+			values[column] = user.Name
+		case "email":
+			values[column] = user.Email
+		case "password":
+			values[column] = user.Password
+		}
+	}
+
 	// Check if user exists
 	result := db.WithContext(ctx).Where("id = ?", user.ID).First(&updatedUser)
 	if result.Error != nil {
@@ -64,23 +86,29 @@ func UpdateUser(ctx *gin.Context, user *models.User) (models.User, error) {
 	}
 
 	// Update user fields
-	result = db.WithContext(ctx).Model(&updatedUser).Updates(user)
+	result = db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND version = ?", user.ID, user.Version).
+		Updates(values)
 	if result.Error != nil {
 		return updatedUser, result.Error
 	}
+	if result.RowsAffected == 0 {
+		return updatedUser, ErrVersionConflict
+	}
 
 	// Fetch the updated user
 	db.WithContext(ctx).Where("id = ?", user.ID).First(&updatedUser)
 	return updatedUser, nil
 }
 
-func DeleteUser(ctx *gin.Context, id string) error {
-	result := db.WithContext(ctx).Where("id = ?", id).Delete(&models.User{})
+// DeleteUser soft-deletes a user if they are still at the given version
+func DeleteUser(ctx *gin.Context, id string, version int64) error {
+	result := db.WithContext(ctx).Where("id = ? AND version = ?", id, version).Delete(&models.User{})
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("user not found")
+		return ErrVersionConflict
 	}
 	return nil
 }
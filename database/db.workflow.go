@@ -0,0 +1,68 @@
+package database
+
+import (
+	"Praiseson6065/ocrolus-be/models"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ErrStateConflict is returned when an article changed state concurrently
+var ErrStateConflict = errors.New("article state has changed")
+
+// TransitionArticle moves an article to a new state and records the change,
+// optionally assigning a reviewer at the same time. The update only applies
+// if the article is still in the state it was read in, so two concurrent
+// transitions cannot both succeed.
+func TransitionArticle(ctx *gin.Context, article *models.Article, toState, actorID, comment string, reviewerID *string) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{"state": toState, "version": nextVersion}
+		if reviewerID != nil {
+			updates["reviewer_id"] = *reviewerID
+		}
+		// Leaving the published state cancels any pending unpublish
+		if article.State == models.ArticleStatePublished {
+			updates["unpublish_at"] = nil
+		}
+
+		result := tx.Model(&models.Article{}).
+			Where("id = ? AND state = ?", article.ID, article.State).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrStateConflict
+		}
+
+		return tx.Create(&models.ArticleTransition{
+			ArticleID: article.ID,
+			FromState: article.State,
+			ToState:   toState,
+			ActorID:   &actorID,
+			Comment:   comment,
+		}).Error
+	})
+}
+
+// AssignReviewer sets or clears the reviewer of an article
+func AssignReviewer(ctx *gin.Context, articleID string, reviewerID *string) error {
+	return db.WithContext(ctx).Model(&models.Article{}).
+		Where("id = ?", articleID).
+		Updates(map[string]interface{}{"reviewer_id": reviewerID, "version": nextVersion}).Error
+}
+
+// ListArticleTransitions returns the state history of an article, oldest first
+func ListArticleTransitions(ctx *gin.Context, articleID string) ([]models.ArticleTransition, error) {
+	var transitions []models.ArticleTransition
+	err := db.WithContext(ctx).
+		Preload("Actor").
+		Where("article_id = ?", articleID).
+		Order("created_at ASC").
+		Find(&transitions).Error
+	if err != nil {
+		return nil, err
+	}
+	return transitions, nil
+}
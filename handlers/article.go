@@ -1,38 +1,258 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"Praiseson6065/ocrolus-be/database"
 	"Praiseson6065/ocrolus-be/middleware"
 	"Praiseson6065/ocrolus-be/models"
+	"Praiseson6065/ocrolus-be/util"
 
 	"github.com/gin-gonic/gin"
 )
 
 type ArticleHandler struct{}
 
+// New articles always start as drafts; state changes go through the
+// transition endpoint. Scheduled times only apply once an article is approved.
 type CreateArticleRequest struct {
-	Title     string `json:"title" binding:"required"`
-	Content   string `json:"content" binding:"required"`
-	Published bool   `json:"published"`
+	Title       string                `json:"title" binding:"required"`
+	Slug        string                `json:"slug"`
+	Content     string                `json:"content"`
+	Format      string                `json:"format"`
+	Blocks      *models.BlockDocument `json:"blocks"`
+	PublishAt   *time.Time            `json:"publish_at"`
+	UnpublishAt *time.Time            `json:"unpublish_at"`
+	Tags        []string              `json:"tags"`
+	Excerpt     string                `json:"excerpt"`
+	Visibility  string                `json:"visibility"`
+	Password    string                `json:"password,omitempty"`
 }
 
 type UpdateArticleRequest struct {
-	Title     string `json:"title"`
-	Content   string `json:"content"`
-	Published bool   `json:"published"`
+	Title       string                `json:"title"`
+	Slug        string                `json:"slug"`
+	Content     string                `json:"content"`
+	Format      string                `json:"format"`
+	Blocks      *models.BlockDocument `json:"blocks"`
+	PublishAt   *time.Time            `json:"publish_at"`
+	UnpublishAt *time.Time            `json:"unpublish_at"`
+	Tags        []string              `json:"tags"`
+	Excerpt     string                `json:"excerpt"`
+	Visibility  string                `json:"visibility"`
+	Password    string                `json:"password,omitempty"`
+}
+
+// ArticlePatchDocument holds the fields that PATCH requests can change
+type ArticlePatchDocument struct {
+	Title       string                `json:"title"`
+	Slug        string                `json:"slug"`
+	Content     string                `json:"content"`
+	Format      string                `json:"format"`
+	Blocks      *models.BlockDocument `json:"blocks"`
+	PublishAt   *time.Time            `json:"publish_at"`
+	UnpublishAt *time.Time            `json:"unpublish_at"`
+	Tags        []string              `json:"tags"`
+	Excerpt     string                `json:"excerpt"`
+	Visibility  string                `json:"visibility"`
+	Password    string                `json:"password,omitempty"`
 }
 
 type ArticleResponse struct {
-	ID        string       `json:"id"`
-	Title     string       `json:"title"`
-	Content   string       `json:"content"`
-	Published bool         `json:"published"`
-	Author    UserResponse `json:"author,omitempty"`
-	CreatedAt string       `json:"created_at"`
-	UpdatedAt string       `json:"updated_at"`
+	ID              string                `json:"id"`
+	Title           string                `json:"title"`
+	Slug            string                `json:"slug"`
+	Content         string                `json:"content"`
+	Format          string                `json:"format"`
+	Blocks          *models.BlockDocument `json:"blocks,omitempty"`
+	ContentHTML     string                `json:"content_html,omitempty"`
+	ContentMarkdown string                `json:"content_markdown,omitempty"`
+	Excerpt         string                `json:"excerpt,omitempty"`
+	Summary         string                `json:"summary,omitempty"`
+	WordCount       int                   `json:"word_count"`
+	ReadingTime     int                   `json:"reading_time"`
+	Keywords        []string              `json:"keywords,omitempty"`
+	State           string                `json:"state"`
+	Visibility      string                `json:"visibility"`
+	Version         int64                 `json:"version"`
+	CommentsClosed  bool                  `json:"comments_closed"`
+	Hidden          bool                  `json:"hidden,omitempty"`
+	Published       bool                  `json:"published"`
+	PublishAt       string                `json:"publish_at,omitempty"`
+	UnpublishAt     string                `json:"unpublish_at,omitempty"`
+	Tags            []string              `json:"tags"`
+	Author          UserResponse          `json:"author,omitempty"`
+	CoAuthors       []UserResponse        `json:"co_authors,omitempty"`
+	Contributors    []UserResponse        `json:"contributors,omitempty"`
+	Reviewer        *UserResponse         `json:"reviewer,omitempty"`
+	Reactions       map[string]int64      `json:"reactions"`
+	MyReactions     []string              `json:"my_reactions,omitempty"`
+	Series          *SeriesNavigation     `json:"series,omitempty"`
+	EditLock        *EditLockResponse     `json:"edit_lock,omitempty"`
+	CreatedAt       string                `json:"created_at"`
+	UpdatedAt       string                `json:"updated_at"`
+}
+
+// newArticleResponse maps an article model to its public API representation.
+// Private fields are added by articleResponseFor.
+func newArticleResponse(article *models.Article) ArticleResponse {
+	response := ArticleResponse{
+		ID:             article.ID,
+		Title:          article.Title,
+		Slug:           article.Slug,
+		Content:        article.Content,
+		Format:         article.Format,
+		Blocks:         article.Blocks,
+		Excerpt:        article.Excerpt,
+		Summary:        article.Summary,
+		WordCount:      article.WordCount,
+		ReadingTime:    article.ReadingTime,
+		Keywords:       article.Keywords,
+		State:          article.State,
+		Visibility:     article.Visibility,
+		Version:        article.Version,
+		CommentsClosed: article.CommentsClosed,
+		Published:      article.IsPublished(),
+		Tags:           articleTagNames(article),
+		Author: UserResponse{
+			ID:   article.Author.ID,
+			Name: article.Author.Name,
+		},
+		CreatedAt: article.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt: article.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	for _, coAuthor := range article.CoAuthors() {
+		response.CoAuthors = append(response.CoAuthors, UserResponse{
+			ID:   coAuthor.ID,
+			Name: coAuthor.Name,
+		})
+	}
+	if article.Reviewer != nil {
+		response.Reviewer = &UserResponse{
+			ID:   article.Reviewer.ID,
+			Name: article.Reviewer.Name,
+		}
+	}
+	if article.PublishAt != nil {
+		response.PublishAt = article.PublishAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	if article.UnpublishAt != nil {
+		response.UnpublishAt = article.UnpublishAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return response
+}
+
+// articleTagNames lists the tag names of an article, never nil
+func articleTagNames(article *models.Article) []string {
+	names := make([]string, len(article.Tags))
+	for i, tag := range article.Tags {
+		names[i] = tag.Name
+	}
+	return names
+}
+
+// maxContentLength bounds the size in bytes of an article body, or of the
+// text of its blocks. Bodies are rendered synchronously on every save.
+const maxContentLength = 256 * 1024
+
+// validateContent checks that the body matches its format and size, writing
+// a 400 response if it does not. Block documents are validated against their
+// schema.
+func validateContent(ctx *gin.Context, format, content string, blocks *models.BlockDocument) bool {
+	if !models.IsValidContentFormat(format) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Format must be one of markdown, plaintext, html or blocks"})
+		return false
+	}
+
+	if format != models.ContentFormatBlocks {
+		if blocks != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Blocks can only be sent with the blocks format"})
+			return false
+		}
+		if content == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Content is required"})
+			return false
+		}
+		if len(content) > maxContentLength {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Content can be at most " + strconv.Itoa(maxContentLength/1024) + " KB"})
+			return false
+		}
+		return true
+	}
+
+	if blocks == nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Blocks are required for the blocks format"})
+		return false
+	}
+	if err := blocks.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blocks: " + err.Error()})
+		return false
+	}
+	length := 0
+	for _, block := range blocks.Blocks {
+		length += len(block.Text) + len(block.Caption) + len(block.Alt)
+	}
+	if length > maxContentLength {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Content can be at most " + strconv.Itoa(maxContentLength/1024) + " KB"})
+		return false
+	}
+	return true
+}
+
+// Limits on the tags of a single article
+const (
+	maxArticleTags   = 10
+	maxTagNameLength = 40
+)
+
+// maxExcerptLength is the maximum number of characters in an author's excerpt
+const maxExcerptLength = 500
+
+// validateExcerpt checks the length of an author's excerpt, writing a 400
+// response if it is too long
+func validateExcerpt(ctx *gin.Context, excerpt string) bool {
+	if len([]rune(excerpt)) > maxExcerptLength {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Excerpt can be at most " + strconv.Itoa(maxExcerptLength) + " characters long"})
+		return false
+	}
+	return true
+}
+
+// articleTags validates tag names and converts them to tag models, writing a
+// 400 response if they are not acceptable
+func articleTags(ctx *gin.Context, names []string) ([]models.Tag, bool) {
+	if len(names) > maxArticleTags {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "An article can have at most " + strconv.Itoa(maxArticleTags) + " tags"})
+		return nil, false
+	}
+	tags := make([]models.Tag, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if len([]rune(name)) > maxTagNameLength {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Tags can be at most " + strconv.Itoa(maxTagNameLength) + " characters long"})
+			return nil, false
+		}
+		if util.Slugify(name) == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Tags must contain at least one letter or digit"})
+			return nil, false
+		}
+		tags = append(tags, models.Tag{Name: name})
+	}
+	return tags, true
+}
+
+// validateSchedule checks that an unpublish time, if any, comes after the publish time
+func validateSchedule(publishAt, unpublishAt *time.Time) bool {
+	if publishAt != nil && unpublishAt != nil {
+		return unpublishAt.After(*publishAt)
+	}
+	return true
 }
 
 // CreateArticle handles the creation of a new article
@@ -50,21 +270,70 @@ func (h *ArticleHandler) CreateArticle(ctx *gin.Context) {
 		return
 	}
 
+	if !validateSchedule(req.PublishAt, req.UnpublishAt) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unpublish_at must be after publish_at"})
+		return
+	}
+
+	if req.Slug != "" && !util.IsValidSlug(req.Slug) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Slug must be lowercase letters, digits and single hyphens"})
+		return
+	}
+
+	if req.Format == "" {
+		req.Format = models.ContentFormatPlaintext
+		if req.Blocks != nil {
+			req.Format = models.ContentFormatBlocks
+		}
+	}
+	if !validateContent(ctx, req.Format, req.Content, req.Blocks) {
+		return
+	}
+
+	tags, ok := articleTags(ctx, req.Tags)
+	if !ok {
+		return
+	}
+
+	req.Excerpt = strings.TrimSpace(req.Excerpt)
+	if !validateExcerpt(ctx, req.Excerpt) {
+		return
+	}
+
 	article := &models.Article{
-		Title:     req.Title,
-		Content:   req.Content,
-		AuthorID:  userID,
-		Published: req.Published,
+		Title:         req.Title,
+		Slug:          req.Slug,
+		Content:       req.Content,
+		Format:        req.Format,
+		Blocks:        req.Blocks,
+		AuthorID:      userID,
+		State:         models.ArticleStateDraft,
+		PublishAt:     req.PublishAt,
+		UnpublishAt:   req.UnpublishAt,
+		Tags:          tags,
+		CustomExcerpt: req.Excerpt,
+	}
+	if !applyVisibility(ctx, article, req.Visibility, req.Password) {
+		return
 	}
 
 	createdArticleID, err := database.CreateArticle(ctx, article)
+	if errors.Is(err, database.ErrSlugTaken) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Slug is already in use"})
+		return
+	}
+	if respondScreeningRejected(ctx, err) {
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create article: " + err.Error()})
 		return
 	}
 
 	ctx.JSON(http.StatusCreated, gin.H{
-		"articleId": createdArticleID,
+		"articleId":     createdArticleID,
+		"slug":          article.Slug,
+		"heldForReview": article.HeldAt != nil,
 	})
 }
 
@@ -78,27 +347,128 @@ func (h *ArticleHandler) GetArticle(ctx *gin.Context) {
 		return
 	}
 
+	if !checkArticleVisibility(ctx, article) {
+		return
+	}
+
+	h.respondWithArticle(ctx, article)
+}
+
+// GetArticleBySlug handles fetching a single article by its permalink slug.
+// Previous slugs redirect to the article's current slug.
+func (h *ArticleHandler) GetArticleBySlug(ctx *gin.Context) {
+	slug := ctx.Param("slug")
+
+	article, err := database.GetArticleBySlug(ctx, slug)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+
+	if !checkArticleVisibility(ctx, article) {
+		return
+	}
+
+	if article.Slug != slug {
+		ctx.Redirect(http.StatusMovedPermanently, "/api/articles/by-slug/"+url.PathEscape(article.Slug))
+		return
+	}
+
+	h.respondWithArticle(ctx, article)
+}
+
+// respondWithArticle records the view for authenticated users and writes the
+// public representation of a single article
+func (h *ArticleHandler) respondWithArticle(ctx *gin.Context, article *models.Article) {
+	shape, ok := parseArticleShape(ctx)
+	if !ok {
+		return
+	}
+
 	// Record the view if user is authenticated
 	userID := middleware.GetUserID(ctx)
 	if userID != "" {
 		// Ignoring errors for recently viewed as it's not critical
-		_ = database.SaveRecentlyViewedArticle(ctx, userID, id)
+		_ = database.SaveRecentlyViewedArticle(ctx, userID, article.ID)
 	}
 
-	response := ArticleResponse{
-		ID:        article.ID,
-		Title:     article.Title,
-		Content:   article.Content,
-		Published: article.Published,
-		Author: UserResponse{
-			ID:    article.Author.ID,
-			Name:  article.Author.Name,
-		},
-		CreatedAt: article.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: article.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	response := articleResponseFor(ctx, article)
+
+	if shape.embeds("reactions") {
+		responses := []ArticleResponse{response}
+		if err := attachReactions(ctx, responses); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve reactions: " + err.Error()})
+			return
+		}
+		response = responses[0]
 	}
 
-	ctx.JSON(http.StatusOK, response)
+	// Readers whose suggested edits were accepted are credited on the article
+	if shape.embeds("contributors") {
+		contributors, err := database.ListSuggestionContributors(ctx, article.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve contributors: " + err.Error()})
+			return
+		}
+		for _, contributor := range contributors {
+			response.Contributors = append(response.Contributors, UserResponse{
+				ID:   contributor.ID,
+				Name: contributor.Name,
+			})
+		}
+	}
+
+	if shape.embeds("series") {
+		var err error
+		response.Series, err = seriesNavigation(ctx, article.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve series: " + err.Error()})
+			return
+		}
+	}
+
+	// Only people who can edit the article need to know who is editing it
+	if shape.embeds("edit_lock") && canSeeArticleField(ctx, article, audienceMember) {
+		lock, err := database.GetEditLock(ctx, article.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve edit lock: " + err.Error()})
+			return
+		}
+		if lock != nil {
+			response.EditLock = newEditLockResponse(lock, userID)
+		}
+	}
+
+	// Renderings are only included on request to keep responses small.
+	// Selecting them with ?fields counts as a request.
+	renders := splitList(ctx.Query("render"))
+	if shape.fields["content_html"] {
+		renders = append(renders, "html")
+	}
+	if shape.fields["content_markdown"] {
+		renders = append(renders, "markdown")
+	}
+	for _, render := range renders {
+		switch render {
+		case "html":
+			response.ContentHTML = article.ContentHTML
+		case "markdown":
+			if article.Blocks != nil {
+				response.ContentMarkdown = util.RenderBlocksMarkdown(article.Blocks)
+			} else if article.Format == models.ContentFormatMarkdown {
+				response.ContentMarkdown = article.Content
+			}
+		}
+	}
+
+	shaped, err := shape.apply(response)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to prepare response: " + err.Error()})
+		return
+	}
+
+	setETag(ctx, article.Version)
+	ctx.JSON(http.StatusOK, shaped)
 }
 
 // ListArticles handles fetching a paginated list of articles
@@ -108,6 +478,37 @@ func (h *ArticleHandler) ListArticles(ctx *gin.Context) {
 	pageSizeStr := ctx.DefaultQuery("pageSize", "10")
 	onlyMine := ctx.Query("onlyMine")
 	publishedOnly := ctx.Query("publishedOnly")
+	assignedToMe := ctx.Query("assignedToMe")
+
+	// Optional comma-separated editorial state filter
+	var states []string
+	if stateParam := ctx.Query("state"); stateParam != "" {
+		for _, state := range strings.Split(stateParam, ",") {
+			if !models.IsValidArticleState(state) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid state filter: " + state})
+				return
+			}
+			states = append(states, state)
+		}
+	}
+
+	shape, ok := parseArticleShape(ctx)
+	if !ok {
+		return
+	}
+
+	// Optional filter expressions and sort keys, e.g.
+	// ?filter=created>=2024-01-01&filter=tag:go|rust&sort=-updated,title
+	filters, err := database.ParseArticleFilters(ctx.QueryArray("filter"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sorts, err := database.ParseArticleSort(ctx.Query("sort"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	page, err := strconv.Atoi(pageStr)
 	if err != nil || page < 1 {
@@ -122,46 +523,47 @@ func (h *ArticleHandler) ListArticles(ctx *gin.Context) {
 	// Get the authenticated user's ID
 	userID := middleware.GetUserID(ctx)
 
-	var articles []models.Article
-	var total int64
-
 	// Determine which articles to fetch
+	var opts database.ArticleListOptions
 	if publishedOnly == "true" {
 		// Public route - only show published articles
-		articles, total, err = database.ListPublishedArticles(ctx, page, pageSize)
+		opts = database.ArticleListOptions{Published: true, Visibility: listedVisibilities(ctx)}
 	} else if onlyMine == "true" && userID != "" {
-		// User's own articles (published and unpublished)
-		articles, total, err = database.ListArticles(ctx, page, pageSize, userID)
-	} else if userID != "" {
-		// Authenticated user can see all published articles + their own unpublished ones
-		// For simplicity, we'll just show all published articles here
-		articles, total, err = database.ListPublishedArticles(ctx, page, pageSize)
+		// Articles the user wrote or collaborates on, in any state
+		opts = database.ArticleListOptions{
+			MemberID: userID,
+			States:   states,
+		}
+	} else if assignedToMe == "true" && userID != "" {
+		// Articles the user has been asked to review
+		opts = database.ArticleListOptions{
+			ReviewerID: userID,
+			States:     states,
+		}
 	} else {
-		// Unauthenticated users can only see published articles
-		articles, total, err = database.ListPublishedArticles(ctx, page, pageSize)
+		// Everyone else sees published articles
+		opts = database.ArticleListOptions{Published: true, Visibility: listedVisibilities(ctx)}
+	}
+	opts.Filters = filters
+	opts.Sort = sorts
+
+	// Sending a cursor or limit switches to keyset pagination; page numbers
+	// stay the default for older clients
+	if _, hasCursor := ctx.GetQuery("cursor"); hasCursor || ctx.Query("limit") != "" {
+		h.listArticlesByCursor(ctx, opts, shape)
+		return
 	}
 
+	articles, total, err := database.ListArticles(ctx, page, pageSize, opts)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve articles: " + err.Error()})
 		return
 	}
 
-	// Map to response objects
-	responseArticles := make([]ArticleResponse, len(articles))
-	for i, article := range articles {
-		responseArticles[i] = ArticleResponse{
-			ID:        article.ID,
-			Title:     article.Title,
-			Content:   article.Content,
-			Published: article.Published,
-			Author: UserResponse{
-				ID:    article.Author.ID,
-				Name:  article.Author.Name,
-				Email: article.Author.Email,
-			},
-			CreatedAt: article.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt: article.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		}
+	responseArticles, err := listArticleResponses(ctx, articles, shape)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve reactions: " + err.Error()})
+		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{
@@ -172,6 +574,85 @@ func (h *ArticleHandler) ListArticles(ctx *gin.Context) {
 	})
 }
 
+// listArticlesByCursor writes one keyset-paginated page of articles, with
+// next and prev cursors in the body and as RFC 8288 Link headers. The total
+// count is only computed when asked for with count=true.
+func (h *ArticleHandler) listArticlesByCursor(ctx *gin.Context, opts database.ArticleListOptions, shape *articleShape) {
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	page, err := database.ListArticlesByCursor(ctx, opts, ctx.Query("cursor"), limit, ctx.Query("count") == "true")
+	if errors.Is(err, database.ErrInvalidCursor) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+		return
+	}
+	var queryErr *database.QueryError
+	if errors.As(err, &queryErr) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": queryErr.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve articles: " + err.Error()})
+		return
+	}
+
+	responseArticles, err := listArticleResponses(ctx, page.Articles, shape)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve reactions: " + err.Error()})
+		return
+	}
+
+	var links []string
+	if page.NextCursor != "" {
+		links = append(links, cursorLink(ctx, page.NextCursor, "next"))
+	}
+	if page.PrevCursor != "" {
+		links = append(links, cursorLink(ctx, page.PrevCursor, "prev"))
+	}
+	if len(links) > 0 {
+		ctx.Header("Link", strings.Join(links, ", "))
+	}
+
+	response := gin.H{
+		"articles":   responseArticles,
+		"nextCursor": page.NextCursor,
+		"prevCursor": page.PrevCursor,
+		"limit":      limit,
+	}
+	if page.Total != nil {
+		response["totalCount"] = *page.Total
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// cursorLink formats a Link header entry for the current request with its
+// cursor replaced
+func cursorLink(ctx *gin.Context, cursor, rel string) string {
+	target := *ctx.Request.URL
+	query := target.Query()
+	query.Set("cursor", cursor)
+	query.Del("page")
+	target.RawQuery = query.Encode()
+	return "<" + target.RequestURI() + `>; rel="` + rel + `"`
+}
+
+// listArticleResponses maps a page of articles to their API representation,
+// shaped as the request asked
+func listArticleResponses(ctx *gin.Context, articles []models.Article, shape *articleShape) ([]interface{}, error) {
+	responseArticles := make([]ArticleResponse, len(articles))
+	for i := range articles {
+		responseArticles[i] = articleResponseFor(ctx, &articles[i])
+	}
+	if shape.embeds("reactions") {
+		if err := attachReactions(ctx, responseArticles); err != nil {
+			return nil, err
+		}
+	}
+	return shape.applyAll(responseArticles)
+}
+
 // UpdateArticle handles updating an existing article
 func (h *ArticleHandler) UpdateArticle(ctx *gin.Context) {
 	id := ctx.Param("id")
@@ -184,49 +665,270 @@ func (h *ArticleHandler) UpdateArticle(ctx *gin.Context) {
 		return
 	}
 
-	// Verify that the user is the author of the article
-	if existingArticle.AuthorID != userID {
+	// Authors and collaborators with edit access may update the article
+	if !existingArticle.HasAccess(userID, models.CollaboratorEditor) {
 		ctx.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to update this article"})
 		return
 	}
 
+	if !checkIfMatch(ctx, existingArticle.Version) {
+		return
+	}
+
+	if !checkEditLock(ctx, existingArticle.ID) {
+		return
+	}
+
+	// The body is also read as a map to tell omitted schedule fields from
+	// ones sent as null
 	var req UpdateArticleRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
+	var sent map[string]json.RawMessage
+	if err := ctx.ShouldBindBodyWithJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if err := ctx.ShouldBindBodyWithJSON(&sent); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 		return
 	}
 
-	// Update fields if provided
+	if req.Slug != "" && !util.IsValidSlug(req.Slug) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Slug must be lowercase letters, digits and single hyphens"})
+		return
+	}
+
+	// Update fields if provided. A new title regenerates the slug unless
+	// one is given explicitly.
+	if req.Slug != "" {
+		existingArticle.Slug = req.Slug
+	} else if req.Title != "" && req.Title != existingArticle.Title {
+		existingArticle.Slug = ""
+	}
 	if req.Title != "" {
 		existingArticle.Title = req.Title
 	}
 	if req.Content != "" {
+		// Block articles derive their text from the blocks
+		if existingArticle.Format == models.ContentFormatBlocks && req.Format == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Send blocks, or a format, to change a block-based article"})
+			return
+		}
 		existingArticle.Content = req.Content
 	}
-	// Published is a boolean, so we always update it from the request
-	existingArticle.Published = req.Published
+	if req.Blocks != nil && req.Format == "" {
+		req.Format = models.ContentFormatBlocks
+	}
+	if req.Content != "" || req.Format != "" {
+		format := req.Format
+		if format == "" {
+			format = existingArticle.Format
+		}
+		blocks := req.Blocks
+		if blocks == nil && format == existingArticle.Format {
+			blocks = existingArticle.Blocks
+		}
+		if !validateContent(ctx, format, existingArticle.Content, blocks) {
+			return
+		}
+		existingArticle.Format = format
+		existingArticle.Blocks = blocks
+		if format != models.ContentFormatBlocks {
+			existingArticle.Blocks = nil
+		}
+	}
+
+	// Like every other field, the schedule is only replaced when sent; an
+	// explicit null clears it
+	if _, ok := sent["publish_at"]; ok {
+		existingArticle.PublishAt = req.PublishAt
+	}
+	if _, ok := sent["unpublish_at"]; ok {
+		existingArticle.UnpublishAt = req.UnpublishAt
+	}
+	if !validateSchedule(existingArticle.PublishAt, existingArticle.UnpublishAt) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unpublish_at must be after publish_at"})
+		return
+	}
+
+	if !applyVisibility(ctx, existingArticle, req.Visibility, req.Password) {
+		return
+	}
+
+	if req.Excerpt = strings.TrimSpace(req.Excerpt); req.Excerpt != "" {
+		if !validateExcerpt(ctx, req.Excerpt) {
+			return
+		}
+		existingArticle.CustomExcerpt = req.Excerpt
+	}
 
-	updatedArticle, err := database.UpdateArticle(ctx, existingArticle)
+	// Tags are only replaced when sent
+	columns := database.ArticleEditableColumns
+	if req.Tags != nil {
+		tags, ok := articleTags(ctx, req.Tags)
+		if !ok {
+			return
+		}
+		existingArticle.Tags = tags
+		columns = append([]string{"tags"}, columns...)
+	}
+
+	updatedArticle, err := database.UpdateArticleColumns(ctx, existingArticle, columns)
+	if errors.Is(err, database.ErrSlugTaken) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Slug is already in use"})
+		return
+	}
+	if respondScreeningRejected(ctx, err) {
+		return
+	}
+	if errors.Is(err, database.ErrVersionConflict) {
+		h.respondArticleConflict(ctx, id)
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update article: " + err.Error()})
 		return
 	}
 
-	response := ArticleResponse{
-		ID:        updatedArticle.ID,
-		Title:     updatedArticle.Title,
-		Content:   updatedArticle.Content,
-		Published: updatedArticle.Published,
-		Author: UserResponse{
-			ID:    updatedArticle.Author.ID,
-			Name:  updatedArticle.Author.Name,
-			Email: updatedArticle.Author.Email,
-		},
-		CreatedAt: updatedArticle.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: updatedArticle.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	setETag(ctx, updatedArticle.Version)
+	ctx.JSON(http.StatusOK, articleResponseFor(ctx, updatedArticle))
+}
+
+// PatchArticle applies a merge patch or JSON patch to an article. Only the
+// fields the patch changes are validated and written.
+func (h *ArticleHandler) PatchArticle(ctx *gin.Context) {
+	id := ctx.Param("id")
+	userID := middleware.GetUserID(ctx)
+
+	existingArticle, err := database.GetArticleByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
 	}
 
-	ctx.JSON(http.StatusOK, response)
+	if !existingArticle.HasAccess(userID, models.CollaboratorEditor) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to update this article"})
+		return
+	}
+
+	if !checkIfMatch(ctx, existingArticle.Version) {
+		return
+	}
+
+	if !checkEditLock(ctx, existingArticle.ID) {
+		return
+	}
+
+	document := ArticlePatchDocument{
+		Title:       existingArticle.Title,
+		Slug:        existingArticle.Slug,
+		Content:     existingArticle.Content,
+		Format:      existingArticle.Format,
+		Blocks:      existingArticle.Blocks,
+		PublishAt:   existingArticle.PublishAt,
+		UnpublishAt: existingArticle.UnpublishAt,
+		Tags:        articleTagNames(existingArticle),
+		Excerpt:     existingArticle.CustomExcerpt,
+		Visibility:  existingArticle.Visibility,
+	}
+	var patched ArticlePatchDocument
+	changed, ok := applyPatchRequest(ctx, document, &patched)
+	if !ok {
+		return
+	}
+
+	if len(changed) == 0 {
+		setETag(ctx, existingArticle.Version)
+		ctx.JSON(http.StatusOK, articleResponseFor(ctx, existingArticle))
+		return
+	}
+
+	var columns []string
+	if changed["title"] {
+		if patched.Title == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Title cannot be empty"})
+			return
+		}
+		existingArticle.Title = patched.Title
+		columns = append(columns, "title")
+		// A new title regenerates the slug unless the patch sets one
+		if !changed["slug"] {
+			existingArticle.Slug = ""
+			columns = append(columns, "slug")
+		}
+	}
+	if changed["slug"] {
+		if patched.Slug != "" && !util.IsValidSlug(patched.Slug) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Slug must be lowercase letters, digits and single hyphens"})
+			return
+		}
+		existingArticle.Slug = patched.Slug
+		columns = append(columns, "slug")
+	}
+	if changed["content"] || changed["format"] || changed["blocks"] {
+		// Block articles derive their text from the blocks
+		if changed["content"] && patched.Format == models.ContentFormatBlocks {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Patch blocks, not content, to change a block-based article"})
+			return
+		}
+		if !validateContent(ctx, patched.Format, patched.Content, patched.Blocks) {
+			return
+		}
+		existingArticle.Content = patched.Content
+		existingArticle.Format = patched.Format
+		existingArticle.Blocks = patched.Blocks
+		columns = append(columns, "content", "format", "blocks")
+	}
+	if changed["publish_at"] || changed["unpublish_at"] {
+		if !validateSchedule(patched.PublishAt, patched.UnpublishAt) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "unpublish_at must be after publish_at"})
+			return
+		}
+		existingArticle.PublishAt = patched.PublishAt
+		existingArticle.UnpublishAt = patched.UnpublishAt
+		columns = append(columns, "publish_at", "unpublish_at")
+	}
+	if changed["excerpt"] {
+		patched.Excerpt = strings.TrimSpace(patched.Excerpt)
+		if !validateExcerpt(ctx, patched.Excerpt) {
+			return
+		}
+		existingArticle.CustomExcerpt = patched.Excerpt
+		columns = append(columns, "excerpt")
+	}
+	if changed["visibility"] || changed["password"] {
+		if !applyVisibility(ctx, existingArticle, patched.Visibility, patched.Password) {
+			return
+		}
+		columns = append(columns, "visibility")
+	}
+	if changed["tags"] {
+		tags, ok := articleTags(ctx, patched.Tags)
+		if !ok {
+			return
+		}
+		existingArticle.Tags = tags
+		columns = append(columns, "tags")
+	}
+
+	updatedArticle, err := database.UpdateArticleColumns(ctx, existingArticle, columns)
+	if errors.Is(err, database.ErrSlugTaken) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Slug is already in use"})
+		return
+	}
+	if respondScreeningRejected(ctx, err) {
+		return
+	}
+	if errors.Is(err, database.ErrVersionConflict) {
+		h.respondArticleConflict(ctx, id)
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update article: " + err.Error()})
+		return
+	}
+
+	setETag(ctx, updatedArticle.Version)
+	ctx.JSON(http.StatusOK, articleResponseFor(ctx, updatedArticle))
 }
 
 // DeleteArticle handles the deletion of an article
@@ -234,20 +936,29 @@ func (h *ArticleHandler) DeleteArticle(ctx *gin.Context) {
 	id := ctx.Param("id")
 	userID := middleware.GetUserID(ctx)
 
-	// Check if article exists and user is the author
+	// Check if article exists and user is an owner
 	existingArticle, err := database.GetArticleByID(ctx, id)
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
 		return
 	}
 
-	// Verify that the user is the author of the article
-	if existingArticle.AuthorID != userID {
+	// Only owners may delete the article
+	if !existingArticle.HasAccess(userID, models.CollaboratorOwner) {
 		ctx.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to delete this article"})
 		return
 	}
 
-	if err := database.DeleteArticle(ctx, id); err != nil {
+	if !checkIfMatch(ctx, existingArticle.Version) {
+		return
+	}
+
+	err = database.DeleteArticle(ctx, id, existingArticle.Version)
+	if errors.Is(err, database.ErrVersionConflict) {
+		h.respondArticleConflict(ctx, id)
+		return
+	}
+	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete article: " + err.Error()})
 		return
 	}
@@ -255,6 +966,17 @@ func (h *ArticleHandler) DeleteArticle(ctx *gin.Context) {
 	ctx.Status(http.StatusNoContent)
 }
 
+// respondArticleConflict reports that an article changed while a request was
+// being processed, along with its current version
+func (h *ArticleHandler) respondArticleConflict(ctx *gin.Context, id string) {
+	current, err := database.GetArticleByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+	respondPreconditionFailed(ctx, current.Version)
+}
+
 // GetRecentlyViewedArticles returns a list of recently viewed articles for the authenticated user
 func (h *ArticleHandler) GetRecentlyViewedArticles(ctx *gin.Context) {
 	userID := middleware.GetUserID(ctx)
@@ -263,6 +985,11 @@ func (h *ArticleHandler) GetRecentlyViewedArticles(ctx *gin.Context) {
 		return
 	}
 
+	shape, ok := parseArticleShape(ctx)
+	if !ok {
+		return
+	}
+
 	limitStr := ctx.DefaultQuery("limit", "5")
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit < 1 || limit > 20 {
@@ -276,21 +1003,10 @@ func (h *ArticleHandler) GetRecentlyViewedArticles(ctx *gin.Context) {
 	}
 
 	// Map to response objects
-	responseArticles := make([]ArticleResponse, len(articles))
-	for i, article := range articles {
-		responseArticles[i] = ArticleResponse{
-			ID:        article.ID,
-			Title:     article.Title,
-			Content:   article.Content,
-			Published: article.Published,
-			Author: UserResponse{
-				ID:    article.Author.ID,
-				Name:  article.Author.Name,
-				Email: article.Author.Email,
-			},
-			CreatedAt: article.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt: article.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		}
+	responseArticles, err := listArticleResponses(ctx, articles, shape)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve reactions: " + err.Error()})
+		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{
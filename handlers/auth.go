@@ -6,6 +6,7 @@ import (
 	"Praiseson6065/ocrolus-be/models"
 	"Praiseson6065/ocrolus-be/util"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -46,6 +47,13 @@ func (h *AuthHandler) UserLogin(ctx *gin.Context) {
 		})
 		return
 	}
+	if user, err := database.GetUserByID(ctx, userId); err == nil && user.IsSuspended(time.Now()) {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error": "Your account has been suspended",
+		})
+		return
+	}
+
 	token, err := middleware.GenerateToken(userId)
 
 	if err != nil {
@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"Praiseson6065/ocrolus-be/database"
+	"Praiseson6065/ocrolus-be/middleware"
+	"Praiseson6065/ocrolus-be/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CollaboratorHandler struct{}
+
+// Collaborators are invited by user ID or by email
+type AddCollaboratorRequest struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email"`
+	Role   string `json:"role" binding:"required"`
+}
+
+type UpdateCollaboratorRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+type TransferOwnershipRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+}
+
+type CollaboratorResponse struct {
+	User      UserResponse `json:"user"`
+	Role      string       `json:"role"`
+	InvitedBy string       `json:"invited_by,omitempty"`
+	CreatedAt string       `json:"created_at"`
+}
+
+func newCollaboratorResponse(collaborator *models.ArticleCollaborator) CollaboratorResponse {
+	return CollaboratorResponse{
+		User: UserResponse{
+			ID:   collaborator.User.ID,
+			Name: collaborator.User.Name,
+		},
+		Role:      collaborator.Role,
+		InvitedBy: collaborator.InvitedByID,
+		CreatedAt: collaborator.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// loadArticleWithAccess fetches the article in the URL and checks that the
+// caller holds at least the given access level on it
+func loadArticleWithAccess(ctx *gin.Context, level string) (*models.Article, bool) {
+	article, err := database.GetArticleByID(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return nil, false
+	}
+	if !article.HasAccess(middleware.GetUserID(ctx), level) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to do this on this article"})
+		return nil, false
+	}
+	return article, true
+}
+
+// ListCollaborators returns the people with access to an article
+func (h *CollaboratorHandler) ListCollaborators(ctx *gin.Context) {
+	article, ok := loadArticleWithAccess(ctx, models.CollaboratorViewer)
+	if !ok {
+		return
+	}
+
+	collaborators, err := database.ListCollaborators(ctx, article.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve collaborators: " + err.Error()})
+		return
+	}
+
+	response := make([]CollaboratorResponse, len(collaborators))
+	for i := range collaborators {
+		response[i] = newCollaboratorResponse(&collaborators[i])
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"author": UserResponse{
+			ID:   article.Author.ID,
+			Name: article.Author.Name,
+		},
+		"collaborators": response,
+	})
+}
+
+// AddCollaborator invites a user to an article with the given access level
+func (h *CollaboratorHandler) AddCollaborator(ctx *gin.Context) {
+	article, ok := loadArticleWithAccess(ctx, models.CollaboratorOwner)
+	if !ok {
+		return
+	}
+
+	var req AddCollaboratorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if !models.IsValidCollaboratorRole(req.Role) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role: " + req.Role})
+		return
+	}
+
+	var user *models.User
+	var err error
+	switch {
+	case req.UserID != "":
+		user, err = database.GetUserByID(ctx, req.UserID)
+	case req.Email != "":
+		user, err = database.GetUserByEmail(ctx, req.Email)
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "user_id or email is required"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if user.ID == article.AuthorID {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "The author already owns this article"})
+		return
+	}
+
+	collaborator, err := database.AddCollaborator(ctx, &models.ArticleCollaborator{
+		ArticleID:   article.ID,
+		UserID:      user.ID,
+		Role:        req.Role,
+		InvitedByID: middleware.GetUserID(ctx),
+	})
+	if errors.Is(err, database.ErrAlreadyCollaborator) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "User is already a collaborator"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add collaborator: " + err.Error()})
+		return
+	}
+
+	collaborator.User = *user
+	ctx.JSON(http.StatusCreated, newCollaboratorResponse(collaborator))
+}
+
+// UpdateCollaborator changes a collaborator's access level
+func (h *CollaboratorHandler) UpdateCollaborator(ctx *gin.Context) {
+	article, ok := loadArticleWithAccess(ctx, models.CollaboratorOwner)
+	if !ok {
+		return
+	}
+
+	var req UpdateCollaboratorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if !models.IsValidCollaboratorRole(req.Role) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role: " + req.Role})
+		return
+	}
+
+	if err := database.UpdateCollaboratorRole(ctx, article.ID, ctx.Param("userId"), req.Role); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Collaborator not found"})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// RemoveCollaborator revokes a collaborator's access. Owners can remove
+// anyone; collaborators can remove themselves.
+func (h *CollaboratorHandler) RemoveCollaborator(ctx *gin.Context) {
+	article, err := database.GetArticleByID(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	targetID := ctx.Param("userId")
+	if targetID != userID && !article.HasAccess(userID, models.CollaboratorOwner) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to remove this collaborator"})
+		return
+	}
+
+	if err := database.RemoveCollaborator(ctx, article.ID, targetID); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Collaborator not found"})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// TransferOwnership hands the article to another user. Only the current
+// author can do this, and they remain on the article as an owner.
+func (h *CollaboratorHandler) TransferOwnership(ctx *gin.Context) {
+	article, err := database.GetArticleByID(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	if article.AuthorID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Only the author can transfer ownership"})
+		return
+	}
+
+	var req TransferOwnershipRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if req.UserID == userID {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "You already own this article"})
+		return
+	}
+	if _, err := database.GetUserByID(ctx, req.UserID); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	err = database.TransferArticleOwnership(ctx, article.ID, userID, req.UserID)
+	if errors.Is(err, database.ErrNotAuthor) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Article ownership changed, please reload"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to transfer ownership: " + err.Error()})
+		return
+	}
+
+	updatedArticle, err := database.GetArticleByID(ctx, article.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve article: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, articleResponseFor(ctx, updatedArticle))
+}
@@ -0,0 +1,313 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"Praiseson6065/ocrolus-be/config"
+	"Praiseson6065/ocrolus-be/database"
+	"Praiseson6065/ocrolus-be/middleware"
+	"Praiseson6065/ocrolus-be/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CommentHandler struct{}
+
+type CreateCommentRequest struct {
+	Body     string  `json:"body" binding:"required"`
+	ParentID *string `json:"parent_id"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+type CommentSettingsRequest struct {
+	Closed bool `json:"closed"`
+}
+
+type CommentResponse struct {
+	ID         string        `json:"id"`
+	ParentID   *string       `json:"parent_id,omitempty"`
+	Body       string        `json:"body"`
+	Author     *UserResponse `json:"author,omitempty"`
+	ReplyCount int64         `json:"reply_count"`
+	Deleted    bool          `json:"deleted"`
+	EditedAt   string        `json:"edited_at,omitempty"`
+	CreatedAt  string        `json:"created_at"`
+}
+
+// maxCommentLength bounds the size of a single comment body
+const maxCommentLength = 10000
+
+// newCommentResponse maps a comment to its API representation. Deleted
+// comments keep their place in the thread but lose their body and author.
+func newCommentResponse(comment *models.Comment) CommentResponse {
+	response := CommentResponse{
+		ID:         comment.ID,
+		ParentID:   comment.ParentID,
+		ReplyCount: comment.ReplyCount,
+		CreatedAt:  comment.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if comment.DeletedAt.Valid {
+		response.Deleted = true
+		return response
+	}
+
+	response.Body = comment.Body
+	response.Author = &UserResponse{
+		ID:   comment.Author.ID,
+		Name: comment.Author.Name,
+	}
+	if comment.EditedAt != nil {
+		response.EditedAt = comment.EditedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return response
+}
+
+// commentPagination reads page and pageSize query parameters
+func commentPagination(ctx *gin.Context) (int, int) {
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(ctx.DefaultQuery("pageSize", "20"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return page, pageSize
+}
+
+// loadArticleComment fetches a comment and checks that it belongs to the
+// article in the URL
+func loadArticleComment(ctx *gin.Context) (*models.Comment, bool) {
+	comment, err := database.GetCommentByID(ctx, ctx.Param("commentId"))
+	if err != nil || comment.ArticleID != ctx.Param("id") {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return nil, false
+	}
+	return comment, true
+}
+
+// ListComments returns a page of top-level comments with their reply counts
+func (h *CommentHandler) ListComments(ctx *gin.Context) {
+	articleID := ctx.Param("id")
+	article, err := database.GetArticleByID(ctx, articleID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+
+	// Comments are as private as the article they are on
+	if !checkArticleVisibility(ctx, article) {
+		return
+	}
+
+	page, pageSize := commentPagination(ctx)
+	comments, total, err := database.ListComments(ctx, articleID, page, pageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve comments: " + err.Error()})
+		return
+	}
+
+	responseComments := make([]CommentResponse, len(comments))
+	for i := range comments {
+		responseComments[i] = newCommentResponse(&comments[i])
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"comments":    responseComments,
+		"totalCount":  total,
+		"currentPage": page,
+		"pageSize":    pageSize,
+	})
+}
+
+// ListReplies returns a page of direct replies to a comment
+func (h *CommentHandler) ListReplies(ctx *gin.Context) {
+	article, err := database.GetArticleByID(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+	if !checkArticleVisibility(ctx, article) {
+		return
+	}
+
+	page, pageSize := commentPagination(ctx)
+	replies, total, err := database.ListReplies(ctx, ctx.Param("id"), ctx.Param("commentId"), page, pageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve replies: " + err.Error()})
+		return
+	}
+
+	responseReplies := make([]CommentResponse, len(replies))
+	for i := range replies {
+		responseReplies[i] = newCommentResponse(&replies[i])
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"replies":     responseReplies,
+		"totalCount":  total,
+		"currentPage": page,
+		"pageSize":    pageSize,
+	})
+}
+
+// CreateComment posts a new comment or reply on a published article
+func (h *CommentHandler) CreateComment(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	article, err := database.GetArticleByID(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+	if !checkArticleVisibility(ctx, article) {
+		return
+	}
+	if !article.IsLive(time.Now()) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Comments are only allowed on published articles"})
+		return
+	}
+	if article.CommentsClosed {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Comments are closed on this article"})
+		return
+	}
+
+	var req CreateCommentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	req.Body = strings.TrimSpace(req.Body)
+	if req.Body == "" || len(req.Body) > maxCommentLength {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Comment body must be between 1 and 10000 characters"})
+		return
+	}
+
+	// Replies must point at a live comment on the same article
+	if req.ParentID != nil {
+		parent, err := database.GetCommentByID(ctx, *req.ParentID)
+		if err != nil || parent.ArticleID != article.ID {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Parent comment not found"})
+			return
+		}
+	}
+
+	comment, err := database.CreateComment(ctx, &models.Comment{
+		ArticleID: article.ID,
+		ParentID:  req.ParentID,
+		AuthorID:  userID,
+		Body:      req.Body,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newCommentResponse(comment))
+}
+
+// UpdateComment edits a comment within the configured edit window
+func (h *CommentHandler) UpdateComment(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+
+	comment, ok := loadArticleComment(ctx)
+	if !ok {
+		return
+	}
+
+	if comment.AuthorID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to edit this comment"})
+		return
+	}
+
+	editWindow := time.Duration(config.Config.Comments.EditWindow) * time.Minute
+	if time.Since(comment.CreatedAt) > editWindow {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "The edit window for this comment has passed"})
+		return
+	}
+
+	var req UpdateCommentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	req.Body = strings.TrimSpace(req.Body)
+	if req.Body == "" || len(req.Body) > maxCommentLength {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Comment body must be between 1 and 10000 characters"})
+		return
+	}
+
+	now := time.Now()
+	comment.Body = req.Body
+	comment.EditedAt = &now
+
+	updatedComment, err := database.UpdateComment(ctx, comment)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update comment: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newCommentResponse(updatedComment))
+}
+
+// DeleteComment soft-deletes a comment. The comment's author, the article's
+// author and editors may delete it.
+func (h *CommentHandler) DeleteComment(ctx *gin.Context) {
+	comment, ok := loadArticleComment(ctx)
+	if !ok {
+		return
+	}
+
+	article, user, ok := loadWorkflowContext(ctx)
+	if !ok {
+		return
+	}
+
+	if comment.AuthorID != user.ID && !article.HasAccess(user.ID, models.CollaboratorEditor) && !user.IsEditor() {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to delete this comment"})
+		return
+	}
+
+	if err := database.DeleteComment(ctx, comment.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment: " + err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// UpdateCommentSettings lets the article's author open or close comments
+func (h *CommentHandler) UpdateCommentSettings(ctx *gin.Context) {
+	article, user, ok := loadWorkflowContext(ctx)
+	if !ok {
+		return
+	}
+
+	if !article.HasAccess(user.ID, models.CollaboratorEditor) && !user.IsEditor() {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to change comment settings"})
+		return
+	}
+
+	var req CommentSettingsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := database.SetCommentsClosed(ctx, article.ID, req.Closed); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update comment settings: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"closed": req.Closed})
+}
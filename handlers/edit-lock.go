@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"Praiseson6065/ocrolus-be/config"
+	"Praiseson6065/ocrolus-be/database"
+	"Praiseson6065/ocrolus-be/middleware"
+	"Praiseson6065/ocrolus-be/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EditLockHandler struct{}
+
+type EditLockResponse struct {
+	Holder      UserResponse `json:"holder"`
+	IsMine      bool         `json:"is_mine"`
+	AcquiredAt  string       `json:"acquired_at"`
+	HeartbeatAt string       `json:"heartbeat_at"`
+	ExpiresAt   string       `json:"expires_at"`
+}
+
+func newEditLockResponse(lock *models.ArticleEditLock, userID string) *EditLockResponse {
+	return &EditLockResponse{
+		Holder: UserResponse{
+			ID:   lock.Holder.ID,
+			Name: lock.Holder.Name,
+		},
+		IsMine:      lock.HolderID == userID,
+		AcquiredAt:  lock.AcquiredAt.Format("2006-01-02T15:04:05Z07:00"),
+		HeartbeatAt: lock.HeartbeatAt.Format("2006-01-02T15:04:05Z07:00"),
+		ExpiresAt:   lock.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// respondLocked tells the caller who is currently editing the article, if
+// the lock could still be read
+func respondLocked(ctx *gin.Context, lock *models.ArticleEditLock) {
+	if lock == nil {
+		ctx.JSON(http.StatusLocked, gin.H{"error": "Someone else is editing this article"})
+		return
+	}
+	ctx.JSON(http.StatusLocked, gin.H{
+		"error": lock.Holder.Name + " is editing this article",
+		"lock":  newEditLockResponse(lock, middleware.GetUserID(ctx)),
+	})
+}
+
+// checkEditLock writes a 423 response and returns false if someone other
+// than the caller holds a live edit lock on the article. Editing without
+// taking the lock is allowed as long as nobody else holds it.
+func checkEditLock(ctx *gin.Context, articleID string) bool {
+	lock, err := database.GetEditLock(ctx, articleID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check edit lock: " + err.Error()})
+		return false
+	}
+	if lock != nil && lock.HolderID != middleware.GetUserID(ctx) {
+		respondLocked(ctx, lock)
+		return false
+	}
+	return true
+}
+
+// AcquireLock takes the edit lock on an article, or refreshes it if the
+// caller already holds it
+func (h *EditLockHandler) AcquireLock(ctx *gin.Context) {
+	article, ok := loadArticleWithAccess(ctx, models.CollaboratorEditor)
+	if !ok {
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	lock, err := database.AcquireEditLock(ctx, article.ID, userID, config.Config.EditLocks.Lease)
+	if errors.Is(err, database.ErrLockHeld) {
+		respondLocked(ctx, lock)
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to acquire edit lock: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newEditLockResponse(lock, userID))
+}
+
+// RenewLock extends the caller's edit lock. Clients call this periodically
+// while the editor is open.
+func (h *EditLockHandler) RenewLock(ctx *gin.Context) {
+	article, ok := loadArticleWithAccess(ctx, models.CollaboratorEditor)
+	if !ok {
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	lock, err := database.RenewEditLock(ctx, article.ID, userID, config.Config.EditLocks.Lease)
+	if errors.Is(err, database.ErrLockNotHeld) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "You no longer hold the edit lock"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to renew edit lock: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newEditLockResponse(lock, userID))
+}
+
+// ReleaseLock gives up the caller's edit lock
+func (h *EditLockHandler) ReleaseLock(ctx *gin.Context) {
+	err := database.ReleaseEditLock(ctx, ctx.Param("id"), middleware.GetUserID(ctx))
+	if errors.Is(err, database.ErrLockNotHeld) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "You do not hold the edit lock"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to release edit lock: " + err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ForceReleaseLock removes an article's edit lock whoever holds it. It is
+// meant for admins clearing a lock left behind by an abandoned session.
+func (h *EditLockHandler) ForceReleaseLock(ctx *gin.Context) {
+	if _, err := database.GetArticleByID(ctx, ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+
+	if err := database.ForceReleaseEditLock(ctx, ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to release edit lock: " + err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"Praiseson6065/ocrolus-be/middleware"
+	"Praiseson6065/ocrolus-be/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Audiences a response field can be restricted to
+const (
+	audiencePublic = iota
+	audienceAuthenticated
+	audienceMember // the author and collaborators of the article
+)
+
+// articleFieldPolicy lists the article fields that are not public.
+// newArticleResponse leaves them empty and articleResponseFor fills them in
+// for callers in the field's audience.
+var articleFieldPolicy = []struct {
+	field    string
+	audience int
+	reveal   func(article *models.Article, response *ArticleResponse)
+}{
+	{"author.email", audienceMember, func(article *models.Article, response *ArticleResponse) {
+		response.Author.Email = article.Author.Email
+	}},
+	{"hidden", audienceMember, func(article *models.Article, response *ArticleResponse) {
+		response.Hidden = article.IsHidden()
+	}},
+}
+
+// canSeeArticleField reports whether the caller belongs to the given audience
+// for an article. Membership needs the article's collaborators preloaded.
+func canSeeArticleField(ctx *gin.Context, article *models.Article, audience int) bool {
+	userID := middleware.GetUserID(ctx)
+	switch audience {
+	case audiencePublic:
+		return true
+	case audienceAuthenticated:
+		return userID != ""
+	default:
+		return article.HasAccess(userID, models.CollaboratorViewer)
+	}
+}
+
+// articleResponseFor maps an article to its API representation, including
+// the private fields the caller is allowed to see
+func articleResponseFor(ctx *gin.Context, article *models.Article) ArticleResponse {
+	response := newArticleResponse(article)
+	for _, policy := range articleFieldPolicy {
+		if canSeeArticleField(ctx, article, policy.audience) {
+			policy.reveal(article, &response)
+		}
+	}
+	return response
+}
+
+// articleResponseFields are the plain fields ?fields= can select
+var articleResponseFields = []string{
+	"id", "title", "slug", "content", "format", "blocks", "content_html", "content_markdown",
+	"excerpt", "summary", "word_count", "reading_time", "keywords", "state", "version", "comments_closed",
+	"published", "publish_at", "unpublish_at", "hidden", "created_at", "updated_at",
+}
+
+// articleEmbeds are the related resources ?include= can select, with the
+// response keys each of them fills
+var articleEmbeds = map[string][]string{
+	"author":       {"author"},
+	"co_authors":   {"co_authors"},
+	"contributors": {"contributors"},
+	"reviewer":     {"reviewer"},
+	"tags":         {"tags"},
+	"reactions":    {"reactions", "my_reactions"},
+	"series":       {"series"},
+	"edit_lock":    {"edit_lock"},
+}
+
+// articleShape is the part of an article response a request asked for.
+// Without ?fields or ?include the full response is returned. ?fields alone
+// returns just those fields, with no embedded resources unless ?include
+// names them; ?include alone returns every field plus the named resources.
+type articleShape struct {
+	fields  map[string]bool // nil selects every field
+	include map[string]bool // nil selects every embedded resource
+}
+
+// parseArticleShape reads ?fields and ?include, writing a 400 response if
+// they name anything unknown
+func parseArticleShape(ctx *gin.Context) (*articleShape, bool) {
+	shape := &articleShape{}
+
+	fieldsParam, hasFields := ctx.GetQuery("fields")
+	includeParam, hasInclude := ctx.GetQuery("include")
+
+	if hasFields {
+		// The id is always returned so that results can be told apart
+		shape.fields = map[string]bool{"id": true}
+		for _, field := range splitList(fieldsParam) {
+			if !containsString(articleResponseFields, field) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Unknown field: " + field})
+				return nil, false
+			}
+			shape.fields[field] = true
+		}
+	}
+
+	if hasFields || hasInclude {
+		shape.include = map[string]bool{}
+		for _, embed := range splitList(includeParam) {
+			if _, ok := articleEmbeds[embed]; !ok {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Unknown include: " + embed})
+				return nil, false
+			}
+			shape.include[embed] = true
+		}
+	}
+
+	return shape, true
+}
+
+// has reports whether a plain field is selected
+func (s *articleShape) has(field string) bool {
+	return s.fields == nil || s.fields[field]
+}
+
+// embeds reports whether an embedded resource is selected, so that work to
+// load unselected ones can be skipped
+func (s *articleShape) embeds(embed string) bool {
+	return s.include == nil || s.include[embed]
+}
+
+// apply drops the parts of a response that were not asked for
+func (s *articleShape) apply(response ArticleResponse) (interface{}, error) {
+	if s.fields == nil && s.include == nil {
+		return response, nil
+	}
+
+	encoded, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &document); err != nil {
+		return nil, err
+	}
+
+	selected := map[string]bool{}
+	for embed, keys := range articleEmbeds {
+		for _, key := range keys {
+			selected[key] = s.embeds(embed)
+		}
+	}
+	for key := range document {
+		if isSelected, isEmbed := selected[key]; isEmbed && !isSelected || !isEmbed && !s.has(key) {
+			delete(document, key)
+		}
+	}
+	return document, nil
+}
+
+// applyAll shapes a list of responses
+func (s *articleShape) applyAll(responses []ArticleResponse) ([]interface{}, error) {
+	shaped := make([]interface{}, len(responses))
+	for i := range responses {
+		var err error
+		if shaped[i], err = s.apply(responses[i]); err != nil {
+			return nil, err
+		}
+	}
+	return shaped, nil
+}
+
+// splitList splits a comma-separated query parameter, ignoring blanks
+func splitList(param string) []string {
+	var values []string
+	for _, value := range strings.Split(param, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,473 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"Praiseson6065/ocrolus-be/database"
+	"Praiseson6065/ocrolus-be/middleware"
+	"Praiseson6065/ocrolus-be/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ModerationHandler struct{}
+
+type CreateReportRequest struct {
+	Reason  string `json:"reason" binding:"required"`
+	Details string `json:"details"`
+}
+
+// A null assignee_id puts the report back in the queue
+type AssignReportRequest struct {
+	AssigneeID *string `json:"assignee_id"`
+}
+
+// SuspendDays only applies to suspensions; 0 suspends until lifted
+type ResolveReportRequest struct {
+	Action      string `json:"action" binding:"required"`
+	Note        string `json:"note"`
+	SuspendDays int    `json:"suspend_days"`
+}
+
+type LiftSuspensionRequest struct {
+	Note string `json:"note"`
+}
+
+type ReportResponse struct {
+	ID             string        `json:"id"`
+	TargetType     string        `json:"target_type"`
+	TargetID       string        `json:"target_id"`
+	TargetUserID   string        `json:"target_user_id"`
+	Reason         string        `json:"reason"`
+	Details        string        `json:"details,omitempty"`
+	Status         string        `json:"status"`
+	Reporter       UserResponse  `json:"reporter"`
+	Assignee       *UserResponse `json:"assignee,omitempty"`
+	Resolution     string        `json:"resolution,omitempty"`
+	ResolutionNote string        `json:"resolution_note,omitempty"`
+	ResolvedBy     *UserResponse `json:"resolved_by,omitempty"`
+	ResolvedAt     string        `json:"resolved_at,omitempty"`
+	CreatedAt      string        `json:"created_at"`
+}
+
+type ModerationActionResponse struct {
+	ID           string        `json:"id"`
+	ReportID     *string       `json:"report_id,omitempty"`
+	Action       string        `json:"action"`
+	TargetType   string        `json:"target_type"`
+	TargetID     string        `json:"target_id"`
+	TargetUserID string        `json:"target_user_id,omitempty"`
+	Note         string        `json:"note,omitempty"`
+	Actor        *UserResponse `json:"actor,omitempty"`
+	CreatedAt    string        `json:"created_at"`
+}
+
+// Bounds on the free text attached to reports and decisions
+const (
+	maxReportDetailsLength  = 2000
+	maxModerationNoteLength = 2000
+	maxSuspendDays          = 365
+)
+
+func newReportResponse(report *models.ContentReport) ReportResponse {
+	response := ReportResponse{
+		ID:           report.ID,
+		TargetType:   report.TargetType,
+		TargetID:     report.TargetID,
+		TargetUserID: report.TargetUserID,
+		Reason:       report.Reason,
+		Details:      report.Details,
+		Status:       report.Status,
+		Reporter: UserResponse{
+			ID:   report.Reporter.ID,
+			Name: report.Reporter.Name,
+		},
+		Resolution:     report.Resolution,
+		ResolutionNote: report.ResolutionNote,
+		CreatedAt:      report.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if report.Assignee != nil {
+		response.Assignee = &UserResponse{
+			ID:   report.Assignee.ID,
+			Name: report.Assignee.Name,
+		}
+	}
+	if report.ResolvedBy != nil {
+		response.ResolvedBy = &UserResponse{
+			ID:   report.ResolvedBy.ID,
+			Name: report.ResolvedBy.Name,
+		}
+	}
+	if report.ResolvedAt != nil {
+		response.ResolvedAt = report.ResolvedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return response
+}
+
+func newModerationActionResponse(action *models.ModerationAction) ModerationActionResponse {
+	response := ModerationActionResponse{
+		ID:           action.ID,
+		ReportID:     action.ReportID,
+		Action:       action.Action,
+		TargetType:   action.TargetType,
+		TargetID:     action.TargetID,
+		TargetUserID: action.TargetUserID,
+		Note:         action.Note,
+		CreatedAt:    action.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if action.Actor != nil {
+		response.Actor = &UserResponse{
+			ID:   action.Actor.ID,
+			Name: action.Actor.Name,
+		}
+	}
+	return response
+}
+
+// isModerator reports whether the caller can work the moderation queue
+func isModerator(ctx *gin.Context) bool {
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		return false
+	}
+	user, err := middleware.GetUser(ctx)
+	return err == nil && user.IsModerator()
+}
+
+// moderationPage reads the page and pageSize query parameters
+func moderationPage(ctx *gin.Context) (int, int) {
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(ctx.DefaultQuery("pageSize", "20"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return page, pageSize
+}
+
+// loadReport fetches the report in the URL
+func loadReport(ctx *gin.Context) (*models.ContentReport, bool) {
+	report, err := database.GetReportByID(ctx, ctx.Param("reportId"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Report not found"})
+		return nil, false
+	}
+	return report, true
+}
+
+// ReportArticle files a report against an article the caller can read.
+// People cannot report articles they are a member of.
+func (h *ModerationHandler) ReportArticle(ctx *gin.Context) {
+	article, err := database.GetArticleByID(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+	if !checkArticleVisibility(ctx, article) {
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	if article.HasAccess(userID, models.CollaboratorViewer) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "You cannot report your own article"})
+		return
+	}
+
+	var req CreateReportRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if !models.IsValidReportReason(req.Reason) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reason: " + req.Reason})
+		return
+	}
+	req.Details = strings.TrimSpace(req.Details)
+	if len(req.Details) > maxReportDetailsLength {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Details are too long"})
+		return
+	}
+
+	report, err := database.CreateReport(ctx, &models.ContentReport{
+		TargetType:   models.ReportTargetArticle,
+		TargetID:     article.ID,
+		TargetUserID: article.AuthorID,
+		ReporterID:   userID,
+		Reason:       req.Reason,
+		Details:      req.Details,
+	})
+	if errors.Is(err, database.ErrAlreadyReported) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "You have already reported this article"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create report: " + err.Error()})
+		return
+	}
+
+	// Reporters only need to know their report was received
+	ctx.JSON(http.StatusCreated, gin.H{
+		"id":         report.ID,
+		"status":     report.Status,
+		"created_at": report.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	})
+}
+
+// ListReports returns the moderation queue. Open and in-review reports are
+// listed unless ?status asks for others; ?assignee takes a user ID, "me" or
+// "none".
+func (h *ModerationHandler) ListReports(ctx *gin.Context) {
+	page, pageSize := moderationPage(ctx)
+
+	opts := database.ReportListOptions{
+		Statuses:   []string{models.ReportOpen, models.ReportInReview},
+		Reason:     ctx.Query("reason"),
+		TargetType: ctx.Query("target_type"),
+		TargetID:   ctx.Query("target_id"),
+	}
+	if statusParam := ctx.Query("status"); statusParam != "" {
+		opts.Statuses = nil
+		for _, status := range strings.Split(statusParam, ",") {
+			if !models.IsValidReportStatus(status) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status filter: " + status})
+				return
+			}
+			opts.Statuses = append(opts.Statuses, status)
+		}
+	}
+	switch assignee := ctx.Query("assignee"); assignee {
+	case "":
+	case "me":
+		opts.AssigneeID = middleware.GetUserID(ctx)
+	case "none":
+		opts.Unassigned = true
+	default:
+		opts.AssigneeID = assignee
+	}
+
+	reports, total, err := database.ListReports(ctx, page, pageSize, opts)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve reports: " + err.Error()})
+		return
+	}
+
+	response := make([]ReportResponse, len(reports))
+	for i := range reports {
+		response[i] = newReportResponse(&reports[i])
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"reports":     response,
+		"totalCount":  total,
+		"currentPage": page,
+		"pageSize":    pageSize,
+	})
+}
+
+// GetReport returns a report together with everything done about it
+func (h *ModerationHandler) GetReport(ctx *gin.Context) {
+	report, ok := loadReport(ctx)
+	if !ok {
+		return
+	}
+
+	actions, _, err := database.ListModerationActions(ctx, 1, 100, database.ModerationActionListOptions{ReportID: report.ID})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve moderation actions: " + err.Error()})
+		return
+	}
+
+	responseActions := make([]ModerationActionResponse, len(actions))
+	for i := range actions {
+		responseActions[i] = newModerationActionResponse(&actions[i])
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"report":  newReportResponse(report),
+		"actions": responseActions,
+	})
+}
+
+// AssignReport hands a report to a moderator, or puts it back in the queue
+func (h *ModerationHandler) AssignReport(ctx *gin.Context) {
+	report, ok := loadReport(ctx)
+	if !ok {
+		return
+	}
+
+	var req AssignReportRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if req.AssigneeID != nil {
+		assignee, err := database.GetUserByID(ctx, *req.AssigneeID)
+		if err != nil || !assignee.IsModerator() {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Reports can only be assigned to moderators"})
+			return
+		}
+	}
+
+	err := database.AssignReport(ctx, report, req.AssigneeID, middleware.GetUserID(ctx))
+	if errors.Is(err, database.ErrReportClosed) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Report is already " + report.Status})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign report: " + err.Error()})
+		return
+	}
+
+	h.respondWithReport(ctx, report.ID)
+}
+
+// ResolveReport closes a report with one of the resolution actions: dismiss,
+// unpublish or delete the article, or warn or suspend its author. Every
+// other open report on the same article is closed with it.
+func (h *ModerationHandler) ResolveReport(ctx *gin.Context) {
+	report, ok := loadReport(ctx)
+	if !ok {
+		return
+	}
+
+	var req ResolveReportRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if !models.IsValidResolution(req.Action) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Action must be one of dismiss, unpublish, delete, warn or suspend"})
+		return
+	}
+	req.Note = strings.TrimSpace(req.Note)
+	if len(req.Note) > maxModerationNoteLength {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Note is too long"})
+		return
+	}
+	if req.SuspendDays != 0 && req.Action != models.ResolutionSuspend {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "suspend_days only applies to suspensions"})
+		return
+	}
+	if req.SuspendDays < 0 || req.SuspendDays > maxSuspendDays {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "suspend_days must be between 0 and " + strconv.Itoa(maxSuspendDays)})
+		return
+	}
+
+	if !report.IsOpen() {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Report is already " + report.Status})
+		return
+	}
+
+	resolution := database.ReportResolution{
+		Action:  req.Action,
+		ActorID: middleware.GetUserID(ctx),
+		Note:    req.Note,
+	}
+	if req.Action == models.ResolutionSuspend {
+		if report.TargetUserID == resolution.ActorID {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "You cannot suspend yourself"})
+			return
+		}
+		if req.SuspendDays > 0 {
+			until := time.Now().Add(time.Duration(req.SuspendDays) * 24 * time.Hour)
+			resolution.SuspendedUntil = &until
+		}
+	}
+
+	err := database.ResolveReport(ctx, report, resolution)
+	if errors.Is(err, database.ErrReportClosed) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Report has already been closed"})
+		return
+	}
+	if errors.Is(err, database.ErrStateConflict) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Only published articles can be unpublished"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve report: " + err.Error()})
+		return
+	}
+
+	h.respondWithReport(ctx, report.ID)
+}
+
+// LiftSuspension ends the suspension of the user in the URL. Suspensions
+// without an end date last until they are lifted here.
+func (h *ModerationHandler) LiftSuspension(ctx *gin.Context) {
+	var req LiftSuspensionRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+	}
+	req.Note = strings.TrimSpace(req.Note)
+	if len(req.Note) > maxModerationNoteLength {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Note is too long"})
+		return
+	}
+
+	user, err := database.GetUserByID(ctx, ctx.Param("userId"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	err = database.LiftSuspension(ctx, user.ID, middleware.GetUserID(ctx), req.Note)
+	if errors.Is(err, database.ErrNotSuspended) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "User is not suspended"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to lift suspension: " + err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListActions returns the moderation log, optionally narrowed to a report,
+// a piece of content, the user responsible for it or the moderator who acted
+func (h *ModerationHandler) ListActions(ctx *gin.Context) {
+	page, pageSize := moderationPage(ctx)
+
+	actions, total, err := database.ListModerationActions(ctx, page, pageSize, database.ModerationActionListOptions{
+		ReportID:     ctx.Query("report_id"),
+		TargetID:     ctx.Query("target_id"),
+		TargetUserID: ctx.Query("user_id"),
+		ActorID:      ctx.Query("actor_id"),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve moderation actions: " + err.Error()})
+		return
+	}
+
+	response := make([]ModerationActionResponse, len(actions))
+	for i := range actions {
+		response[i] = newModerationActionResponse(&actions[i])
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"actions":     response,
+		"totalCount":  total,
+		"currentPage": page,
+		"pageSize":    pageSize,
+	})
+}
+
+func (h *ModerationHandler) respondWithReport(ctx *gin.Context, id string) {
+	report, err := database.GetReportByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve report"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newReportResponse(report))
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"reflect"
+
+	"Praiseson6065/ocrolus-be/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Media types accepted by PATCH endpoints
+const (
+	mediaTypeMergePatch = "application/merge-patch+json"
+	mediaTypeJSONPatch  = "application/json-patch+json"
+)
+
+// applyPatchRequest applies the request body to document, which holds the
+// patchable fields of a resource, and decodes the result into patched. The
+// body is an RFC 7386 merge patch, or an RFC 6902 JSON patch when sent as
+// application/json-patch+json. It returns the JSON names of the fields whose
+// value changed, or writes an error response and returns false.
+func applyPatchRequest(ctx *gin.Context, document interface{}, patched interface{}) (map[string]bool, bool) {
+	mediaType := mediaTypeMergePatch
+	if contentType := ctx.GetHeader("Content-Type"); contentType != "" {
+		parsed, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Invalid Content-Type"})
+			return nil, false
+		}
+		mediaType = parsed
+	}
+
+	body, err := ctx.GetRawData()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return nil, false
+	}
+
+	before, err := json.Marshal(document)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to prepare patch: " + err.Error()})
+		return nil, false
+	}
+
+	var after []byte
+	switch mediaType {
+	case mediaTypeMergePatch, "application/json":
+		after, err = util.ApplyMergePatch(before, body)
+	case mediaTypeJSONPatch:
+		after, err = util.ApplyJSONPatch(before, body)
+	default:
+		ctx.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"error": "Content-Type must be " + mediaTypeMergePatch + " or " + mediaTypeJSONPatch,
+		})
+		return nil, false
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid patch: " + err.Error()})
+		return nil, false
+	}
+
+	// Fields outside the document, such as state, cannot be patched
+	decoder := json.NewDecoder(bytes.NewReader(after))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(patched); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid patch result: " + err.Error()})
+		return nil, false
+	}
+
+	// Compare through a normalized encoding so removed members count as
+	// changes to their zero value
+	normalized, err := json.Marshal(patched)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply patch: " + err.Error()})
+		return nil, false
+	}
+	return changedFields(before, normalized), true
+}
+
+// changedFields lists the top-level members that differ between two JSON objects
+func changedFields(before, after []byte) map[string]bool {
+	var beforeFields, afterFields map[string]interface{}
+	_ = json.Unmarshal(before, &beforeFields)
+	_ = json.Unmarshal(after, &afterFields)
+
+	changed := map[string]bool{}
+	for key, value := range afterFields {
+		if !reflect.DeepEqual(beforeFields[key], value) {
+			changed[key] = true
+		}
+	}
+	return changed
+}
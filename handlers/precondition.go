@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"Praiseson6065/ocrolus-be/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// versionETag formats a row version as a strong entity tag
+func versionETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+func setETag(ctx *gin.Context, version int64) {
+	ctx.Header("ETag", versionETag(version))
+}
+
+// checkIfMatch compares the If-Match header with the current version of a
+// resource, writing a 412 response and returning false if it does not match.
+// Requests without If-Match pass unless the server is configured to require it.
+func checkIfMatch(ctx *gin.Context, version int64) bool {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" {
+		if config.Config.Concurrency.RequireIfMatch {
+			ctx.JSON(http.StatusPreconditionRequired, gin.H{"error": "If-Match header is required"})
+			return false
+		}
+		return true
+	}
+	if header == "*" {
+		return true
+	}
+
+	current := versionETag(version)
+	for _, tag := range strings.Split(header, ",") {
+		// Versions are compared weakly so W/ tags from proxies still match
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == current {
+			return true
+		}
+	}
+
+	respondPreconditionFailed(ctx, version)
+	return false
+}
+
+// respondPreconditionFailed tells the client its copy is out of date and what
+// the current version is
+func respondPreconditionFailed(ctx *gin.Context, version int64) {
+	setETag(ctx, version)
+	ctx.JSON(http.StatusPreconditionFailed, gin.H{
+		"error":          "Resource has been modified",
+		"currentVersion": version,
+	})
+}
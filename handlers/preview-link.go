@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"Praiseson6065/ocrolus-be/database"
+	"Praiseson6065/ocrolus-be/middleware"
+	"Praiseson6065/ocrolus-be/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Lifetime of preview links in hours
+const (
+	defaultPreviewLinkHours = 72
+	maxPreviewLinkHours     = 30 * 24
+)
+
+type PreviewLinkHandler struct{}
+
+type CreatePreviewLinkRequest struct {
+	Label          string `json:"label"`
+	ExpiresInHours int    `json:"expires_in_hours"`
+}
+
+type PreviewLinkResponse struct {
+	ID         string       `json:"id"`
+	Label      string       `json:"label,omitempty"`
+	Token      string       `json:"token,omitempty"`
+	URL        string       `json:"url,omitempty"`
+	CreatedBy  UserResponse `json:"created_by"`
+	Active     bool         `json:"active"`
+	UseCount   int64        `json:"use_count"`
+	LastUsedAt string       `json:"last_used_at,omitempty"`
+	ExpiresAt  string       `json:"expires_at"`
+	RevokedAt  string       `json:"revoked_at,omitempty"`
+	CreatedAt  string       `json:"created_at"`
+}
+
+func newPreviewLinkResponse(link *models.ArticlePreviewLink) PreviewLinkResponse {
+	response := PreviewLinkResponse{
+		ID:    link.ID,
+		Label: link.Label,
+		CreatedBy: UserResponse{
+			ID:   link.CreatedBy.ID,
+			Name: link.CreatedBy.Name,
+		},
+		Active:    link.IsActive(time.Now()),
+		UseCount:  link.UseCount,
+		ExpiresAt: link.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+		CreatedAt: link.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if link.LastUsedAt != nil {
+		response.LastUsedAt = link.LastUsedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	if link.RevokedAt != nil {
+		response.RevokedAt = link.RevokedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return response
+}
+
+// CreatePreviewLink issues a signed preview link for an article. The token
+// is only returned here; lost links can be revoked and replaced.
+func (h *PreviewLinkHandler) CreatePreviewLink(ctx *gin.Context) {
+	article, ok := loadArticleWithAccess(ctx, models.CollaboratorEditor)
+	if !ok {
+		return
+	}
+
+	var req CreatePreviewLinkRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && ctx.Request.ContentLength > 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if req.ExpiresInHours == 0 {
+		req.ExpiresInHours = defaultPreviewLinkHours
+	}
+	if req.ExpiresInHours < 1 || req.ExpiresInHours > maxPreviewLinkHours {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "expires_in_hours must be between 1 and " + strconv.Itoa(maxPreviewLinkHours)})
+		return
+	}
+
+	link := &models.ArticlePreviewLink{
+		ArticleID:   article.ID,
+		CreatedByID: middleware.GetUserID(ctx),
+		Label:       strings.TrimSpace(req.Label),
+		ExpiresAt:   time.Now().Add(time.Duration(req.ExpiresInHours) * time.Hour),
+	}
+	link, err := database.CreatePreviewLink(ctx, link)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create preview link: " + err.Error()})
+		return
+	}
+	if user, err := database.GetUserByID(ctx, link.CreatedByID); err == nil {
+		link.CreatedBy = *user
+	}
+
+	token, err := middleware.GeneratePreviewToken(link.ID, link.ExpiresAt)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sign preview link: " + err.Error()})
+		return
+	}
+
+	response := newPreviewLinkResponse(link)
+	response.Token = token
+	response.URL = "/api/preview/" + token
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// ListPreviewLinks returns an article's preview links with how often each
+// was used
+func (h *PreviewLinkHandler) ListPreviewLinks(ctx *gin.Context) {
+	article, ok := loadArticleWithAccess(ctx, models.CollaboratorEditor)
+	if !ok {
+		return
+	}
+
+	links, err := database.ListPreviewLinks(ctx, article.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve preview links: " + err.Error()})
+		return
+	}
+
+	responseLinks := make([]PreviewLinkResponse, len(links))
+	for i := range links {
+		responseLinks[i] = newPreviewLinkResponse(&links[i])
+	}
+	ctx.JSON(http.StatusOK, gin.H{"preview_links": responseLinks})
+}
+
+// RevokePreviewLink stops a preview link from working
+func (h *PreviewLinkHandler) RevokePreviewLink(ctx *gin.Context) {
+	article, ok := loadArticleWithAccess(ctx, models.CollaboratorEditor)
+	if !ok {
+		return
+	}
+
+	if err := database.RevokePreviewLink(ctx, article.ID, ctx.Param("linkId")); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Preview link not found"})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetPreview returns the article behind a preview link, whatever its state,
+// to anyone holding the token. Articles hidden by moderation are only shown
+// through links created by a moderator. Previews are read-only and are not recorded
+// as views.
+func (h *PreviewLinkHandler) GetPreview(ctx *gin.Context) {
+	linkID, err := middleware.ValidatePreviewToken(ctx.Param("token"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Preview link is invalid or has expired"})
+		return
+	}
+
+	link, err := database.GetActivePreviewLink(ctx, linkID)
+	if errors.Is(err, database.ErrPreviewLinkInactive) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Preview link is invalid or has expired"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open preview link: " + err.Error()})
+		return
+	}
+
+	article, err := database.GetArticleByID(ctx, link.ArticleID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+	// Content hidden by moderation is only previewed through links a
+	// moderator created, so that authors cannot route around the hiding
+	if article.IsHidden() && !previewCreatedByModerator(ctx, link) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+
+	// Only previews that are actually served count as uses
+	link, err = database.UsePreviewLink(ctx, link.ID)
+	if errors.Is(err, database.ErrPreviewLinkInactive) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Preview link is invalid or has expired"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open preview link: " + err.Error()})
+		return
+	}
+
+	response := newArticleResponse(article)
+	response.ContentHTML = article.ContentHTML
+
+	// Drafts shared this way should not end up in caches or search engines
+	ctx.Header("Cache-Control", "private, no-store")
+	ctx.Header("X-Robots-Tag", "noindex, nofollow")
+	ctx.JSON(http.StatusOK, gin.H{
+		"article":    response,
+		"preview":    true,
+		"expires_at": link.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+	})
+}
+
+// previewCreatedByModerator reports whether the link was created by someone
+// who is currently a moderator
+func previewCreatedByModerator(ctx *gin.Context, link *models.ArticlePreviewLink) bool {
+	creator, err := database.GetUserByID(ctx, link.CreatedByID)
+	return err == nil && creator.IsModerator()
+}
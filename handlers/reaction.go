@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"Praiseson6065/ocrolus-be/database"
+	"Praiseson6065/ocrolus-be/middleware"
+	"Praiseson6065/ocrolus-be/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReactionHandler struct{}
+
+// attachReactions fills in reaction totals for each article and, for an
+// authenticated caller, the reactions they left themselves. Totals come from
+// counter rows so a whole page is served by two queries.
+func attachReactions(ctx *gin.Context, responses []ArticleResponse) error {
+	articleIDs := make([]string, len(responses))
+	for i := range responses {
+		articleIDs[i] = responses[i].ID
+	}
+
+	counts, err := database.GetReactionCounts(ctx, articleIDs)
+	if err != nil {
+		return err
+	}
+	mine, err := database.GetUserReactions(ctx, middleware.GetUserID(ctx), articleIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range responses {
+		responses[i].Reactions = counts[responses[i].ID]
+		if responses[i].Reactions == nil {
+			responses[i].Reactions = map[string]int64{}
+		}
+		responses[i].MyReactions = mine[responses[i].ID]
+	}
+	return nil
+}
+
+// AddReaction adds the caller's reaction of the given type. Repeating the
+// request has no further effect.
+func (h *ReactionHandler) AddReaction(ctx *gin.Context) {
+	h.toggleReaction(ctx, true)
+}
+
+// RemoveReaction removes the caller's reaction of the given type, if any
+func (h *ReactionHandler) RemoveReaction(ctx *gin.Context) {
+	h.toggleReaction(ctx, false)
+}
+
+func (h *ReactionHandler) toggleReaction(ctx *gin.Context, add bool) {
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	reactionType := ctx.Param("type")
+	if !models.IsValidReactionType(reactionType) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Unknown reaction type: " + reactionType})
+		return
+	}
+
+	article, err := database.GetArticleByID(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+	if !checkArticleVisibility(ctx, article) {
+		return
+	}
+
+	if add {
+		if !article.IsLive(time.Now()) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "Reactions are only allowed on published articles"})
+			return
+		}
+		err = database.AddReaction(ctx, article.ID, userID, reactionType)
+	} else {
+		err = database.RemoveReaction(ctx, article.ID, userID, reactionType)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update reaction: " + err.Error()})
+		return
+	}
+
+	response := []ArticleResponse{{ID: article.ID}}
+	if err := attachReactions(ctx, response); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve reactions: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"reactions":    response[0].Reactions,
+		"my_reactions": response[0].MyReactions,
+	})
+}
@@ -0,0 +1,376 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"Praiseson6065/ocrolus-be/database"
+	"Praiseson6065/ocrolus-be/middleware"
+	"Praiseson6065/ocrolus-be/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReadingListHandler struct{}
+
+type ReadingListRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	IsPublic    bool   `json:"is_public"`
+}
+
+type AddReadingListItemRequest struct {
+	ArticleID string `json:"article_id" binding:"required"`
+	Note      string `json:"note"`
+}
+
+type UpdateReadingListItemRequest struct {
+	Note string `json:"note"`
+}
+
+type ReorderReadingListRequest struct {
+	ItemIDs []string `json:"item_ids" binding:"required"`
+}
+
+type ReadingListResponse struct {
+	ID          string                    `json:"id"`
+	Name        string                    `json:"name"`
+	Description string                    `json:"description"`
+	IsPublic    bool                      `json:"is_public"`
+	IsDefault   bool                      `json:"is_default"`
+	ItemCount   int64                     `json:"item_count"`
+	Owner       *UserResponse             `json:"owner,omitempty"`
+	Items       []ReadingListItemResponse `json:"items,omitempty"`
+	CreatedAt   string                    `json:"created_at"`
+	UpdatedAt   string                    `json:"updated_at"`
+}
+
+type ReadingListItemResponse struct {
+	ID       string          `json:"id"`
+	Position int             `json:"position"`
+	Note     string          `json:"note"`
+	Article  ArticleResponse `json:"article"`
+	AddedAt  string          `json:"added_at"`
+}
+
+func newReadingListResponse(list *models.ReadingList) ReadingListResponse {
+	return ReadingListResponse{
+		ID:          list.ID,
+		Name:        list.Name,
+		Description: list.Description,
+		IsPublic:    list.IsPublic,
+		IsDefault:   list.IsDefault,
+		ItemCount:   list.ItemCount,
+		CreatedAt:   list.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:   list.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// newReadingListDetailResponse includes the list's items. Other people only
+// see items whose article is still live, not hidden by moderation and would
+// appear in their listings. Owners see all their items, but without the body
+// of articles they can no longer read without a password or a role.
+func newReadingListDetailResponse(list *models.ReadingList, isOwner, authenticated bool) ReadingListResponse {
+	response := newReadingListResponse(list)
+	response.Owner = &UserResponse{ID: list.User.ID, Name: list.User.Name}
+	response.Items = []ReadingListItemResponse{}
+
+	now := time.Now()
+	for i := range list.Items {
+		item := &list.Items[i]
+		// Deleted articles are not preloaded
+		if item.Article.ID == "" {
+			continue
+		}
+		readable := isSavedArticleReadable(&item.Article, list.UserID, now)
+		if !isOwner && (!readable || !item.Article.IsListedFor(authenticated)) {
+			continue
+		}
+
+		article := newArticleResponse(&item.Article)
+		if !readable {
+			article.Content = ""
+			article.Blocks = nil
+			article.Excerpt = ""
+			article.Summary = ""
+		}
+		response.Items = append(response.Items, ReadingListItemResponse{
+			ID:       item.ID,
+			Position: item.Position,
+			Note:     item.Note,
+			Article:  article,
+			AddedAt:  item.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		})
+	}
+	response.ItemCount = int64(len(response.Items))
+	return response
+}
+
+// isSavedArticleReadable reports whether the owner of a list can read a saved
+// article without presenting anything further: always as one of its members,
+// otherwise only while it is live, not hidden and not password protected
+func isSavedArticleReadable(article *models.Article, userID string, now time.Time) bool {
+	if article.HasAccess(userID, models.CollaboratorViewer) {
+		return true
+	}
+	return article.IsLive(now) && !article.IsHidden() && article.Visibility != models.ArticleVisibilityPassword
+}
+
+// loadOwnedReadingList fetches the list in the URL and checks that it belongs
+// to the caller
+func loadOwnedReadingList(ctx *gin.Context) (*models.ReadingList, bool) {
+	list, err := database.GetReadingListByID(ctx, ctx.Param("listId"))
+	if err != nil || list.UserID != middleware.GetUserID(ctx) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Reading list not found"})
+		return nil, false
+	}
+	return list, true
+}
+
+// ListReadingLists returns the caller's reading lists
+func (h *ReadingListHandler) ListReadingLists(ctx *gin.Context) {
+	lists, err := database.ListReadingLists(ctx, middleware.GetUserID(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve reading lists: " + err.Error()})
+		return
+	}
+
+	response := make([]ReadingListResponse, len(lists))
+	for i := range lists {
+		response[i] = newReadingListResponse(&lists[i])
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"readingLists": response,
+	})
+}
+
+// CreateReadingList creates a new reading list for the caller
+func (h *ReadingListHandler) CreateReadingList(ctx *gin.Context) {
+	var req ReadingListRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	list, err := database.CreateReadingList(ctx, &models.ReadingList{
+		UserID:      middleware.GetUserID(ctx),
+		Name:        req.Name,
+		Description: req.Description,
+		IsPublic:    req.IsPublic,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create reading list: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newReadingListResponse(list))
+}
+
+// GetReadingList returns one of the caller's lists with its items
+func (h *ReadingListHandler) GetReadingList(ctx *gin.Context) {
+	list, ok := loadOwnedReadingList(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newReadingListDetailResponse(list, true, true))
+}
+
+// GetSharedReadingList returns a public list to anyone with its link. Owners
+// can also view their private lists here.
+func (h *ReadingListHandler) GetSharedReadingList(ctx *gin.Context) {
+	list, err := database.GetReadingListByID(ctx, ctx.Param("listId"))
+	isOwner := err == nil && list.UserID == middleware.GetUserID(ctx)
+	if err != nil || (!list.IsPublic && !isOwner) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Reading list not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newReadingListDetailResponse(list, isOwner, middleware.GetUserID(ctx) != ""))
+}
+
+// UpdateReadingList changes a list's name, description and visibility
+func (h *ReadingListHandler) UpdateReadingList(ctx *gin.Context) {
+	list, ok := loadOwnedReadingList(ctx)
+	if !ok {
+		return
+	}
+
+	var req ReadingListRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	list.Name = req.Name
+	list.Description = req.Description
+	list.IsPublic = req.IsPublic
+
+	if err := database.UpdateReadingList(ctx, list); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update reading list: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newReadingListDetailResponse(list, true, true))
+}
+
+// DeleteReadingList removes one of the caller's lists
+func (h *ReadingListHandler) DeleteReadingList(ctx *gin.Context) {
+	list, ok := loadOwnedReadingList(ctx)
+	if !ok {
+		return
+	}
+
+	if err := database.DeleteReadingList(ctx, list.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete reading list: " + err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// AddItem saves an article at the end of a list
+func (h *ReadingListHandler) AddItem(ctx *gin.Context) {
+	list, ok := loadOwnedReadingList(ctx)
+	if !ok {
+		return
+	}
+
+	var req AddReadingListItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	// Only articles the caller can read right now can be saved
+	article, err := database.GetArticleByID(ctx, req.ArticleID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+	if !checkArticleVisibility(ctx, article) {
+		return
+	}
+
+	item, err := database.AddReadingListItem(ctx, list.ID, article.ID, req.Note)
+	if errors.Is(err, database.ErrAlreadyInList) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Article is already in this list"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add article: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"id":       item.ID,
+		"position": item.Position,
+	})
+}
+
+// UpdateItem changes the note on a saved article
+func (h *ReadingListHandler) UpdateItem(ctx *gin.Context) {
+	list, ok := loadOwnedReadingList(ctx)
+	if !ok {
+		return
+	}
+
+	var req UpdateReadingListItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := database.UpdateReadingListItemNote(ctx, list.ID, ctx.Param("itemId"), req.Note); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// RemoveItem removes a saved article from a list
+func (h *ReadingListHandler) RemoveItem(ctx *gin.Context) {
+	list, ok := loadOwnedReadingList(ctx)
+	if !ok {
+		return
+	}
+
+	if err := database.RemoveReadingListItem(ctx, list.ID, ctx.Param("itemId")); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ReorderItems sets the order of every item in a list
+func (h *ReadingListHandler) ReorderItems(ctx *gin.Context) {
+	list, ok := loadOwnedReadingList(ctx)
+	if !ok {
+		return
+	}
+
+	var req ReorderReadingListRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	err := database.ReorderReadingListItems(ctx, list.ID, req.ItemIDs)
+	if errors.Is(err, database.ErrInvalidOrder) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "item_ids must list every item in the list exactly once"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder items: " + err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// AddBookmark saves an article to the caller's default bookmarks list
+func (h *ReadingListHandler) AddBookmark(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+
+	article, err := database.GetArticleByID(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+	if !checkArticleVisibility(ctx, article) {
+		return
+	}
+
+	list, err := database.GetOrCreateDefaultReadingList(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load bookmarks: " + err.Error()})
+		return
+	}
+
+	// Bookmarking an already bookmarked article is not an error
+	_, err = database.AddReadingListItem(ctx, list.ID, article.ID, "")
+	if err != nil && !errors.Is(err, database.ErrAlreadyInList) {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to bookmark article: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"bookmarked": true, "listId": list.ID})
+}
+
+// RemoveBookmark removes an article from the caller's default bookmarks list
+func (h *ReadingListHandler) RemoveBookmark(ctx *gin.Context) {
+	list, err := database.GetOrCreateDefaultReadingList(ctx, middleware.GetUserID(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load bookmarks: " + err.Error()})
+		return
+	}
+
+	// Removing a bookmark that does not exist is not an error
+	_ = database.RemoveArticleFromReadingList(ctx, list.ID, ctx.Param("id"))
+
+	ctx.JSON(http.StatusOK, gin.H{"bookmarked": false, "listId": list.ID})
+}
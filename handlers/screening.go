@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"Praiseson6065/ocrolus-be/database"
+	"Praiseson6065/ocrolus-be/middleware"
+	"Praiseson6065/ocrolus-be/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// "approve" releases a held article, "reject" moves it to the trash
+type ReviewScreeningRequest struct {
+	Decision string `json:"decision" binding:"required"`
+	Note     string `json:"note"`
+}
+
+type ScreeningResponse struct {
+	ID           string                  `json:"id"`
+	ArticleID    *string                 `json:"article_id,omitempty"`
+	Title        string                  `json:"title"`
+	Author       UserResponse            `json:"author"`
+	Verdict      string                  `json:"verdict"`
+	Reasons      models.ScreeningReasons `json:"reasons"`
+	ReviewStatus string                  `json:"review_status,omitempty"`
+	ReviewedBy   *UserResponse           `json:"reviewed_by,omitempty"`
+	ReviewedAt   string                  `json:"reviewed_at,omitempty"`
+	CreatedAt    string                  `json:"created_at"`
+}
+
+func newScreeningResponse(record *models.ArticleScreening) ScreeningResponse {
+	response := ScreeningResponse{
+		ID:        record.ID,
+		ArticleID: record.ArticleID,
+		Title:     record.Title,
+		Author: UserResponse{
+			ID:   record.Author.ID,
+			Name: record.Author.Name,
+		},
+		Verdict:      record.Verdict,
+		Reasons:      record.Reasons,
+		ReviewStatus: record.ReviewStatus,
+		CreatedAt:    record.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if response.Reasons == nil {
+		response.Reasons = models.ScreeningReasons{}
+	}
+	if record.ReviewedBy != nil {
+		response.ReviewedBy = &UserResponse{
+			ID:   record.ReviewedBy.ID,
+			Name: record.ReviewedBy.Name,
+		}
+	}
+	if record.ReviewedAt != nil {
+		response.ReviewedAt = record.ReviewedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return response
+}
+
+// respondScreeningRejected writes a 422 response with the screening reasons
+// if err is a rejection by content screening, and reports whether it did
+func respondScreeningRejected(ctx *gin.Context, err error) bool {
+	var rejected *database.ScreeningRejectedError
+	if !errors.As(err, &rejected) {
+		return false
+	}
+	ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+		"error":   "The article was rejected by content screening",
+		"reasons": rejected.Screening.Reasons,
+	})
+	return true
+}
+
+// ListScreenings returns screening verdicts for moderators. Submissions
+// held for review are listed unless ?verdict or ?review_status ask for
+// others.
+func (h *ModerationHandler) ListScreenings(ctx *gin.Context) {
+	page, pageSize := moderationPage(ctx)
+
+	opts := database.ScreeningListOptions{
+		Verdict:      ctx.Query("verdict"),
+		ReviewStatus: ctx.Query("review_status"),
+		AuthorID:     ctx.Query("author_id"),
+		ArticleID:    ctx.Query("article_id"),
+	}
+	if opts.Verdict == "" && opts.ReviewStatus == "" {
+		opts.ReviewStatus = models.ScreeningReviewPending
+	}
+
+	records, total, err := database.ListScreenings(ctx, page, pageSize, opts)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve screenings: " + err.Error()})
+		return
+	}
+
+	response := make([]ScreeningResponse, len(records))
+	for i := range records {
+		response[i] = newScreeningResponse(&records[i])
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"screenings":  response,
+		"totalCount":  total,
+		"currentPage": page,
+		"pageSize":    pageSize,
+	})
+}
+
+// ReviewScreening approves or rejects an article held by content screening
+func (h *ModerationHandler) ReviewScreening(ctx *gin.Context) {
+	record, err := database.GetScreeningByID(ctx, ctx.Param("screeningId"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Screening not found"})
+		return
+	}
+
+	var req ReviewScreeningRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if req.Decision != "approve" && req.Decision != "reject" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Decision must be approve or reject"})
+		return
+	}
+	req.Note = strings.TrimSpace(req.Note)
+	if len(req.Note) > maxModerationNoteLength {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Note is too long"})
+		return
+	}
+
+	if record.ReviewStatus != models.ScreeningReviewPending {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "This submission is not waiting for review"})
+		return
+	}
+
+	err = database.ReviewScreening(ctx, record, req.Decision == "approve", middleware.GetUserID(ctx), req.Note)
+	if errors.Is(err, database.ErrScreeningReviewed) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "This submission has already been reviewed"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to review submission: " + err.Error()})
+		return
+	}
+
+	updated, err := database.GetScreeningByID(ctx, record.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve screening"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newScreeningResponse(updated))
+}
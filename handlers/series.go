@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"Praiseson6065/ocrolus-be/database"
+	"Praiseson6065/ocrolus-be/middleware"
+	"Praiseson6065/ocrolus-be/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SeriesHandler struct{}
+
+type SeriesRequest struct {
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description"`
+}
+
+type AddSeriesPartRequest struct {
+	ArticleID string `json:"article_id" binding:"required"`
+}
+
+type ReorderSeriesRequest struct {
+	ArticleIDs []string `json:"article_ids" binding:"required"`
+}
+
+type SeriesResponse struct {
+	ID          string               `json:"id"`
+	Title       string               `json:"title"`
+	Description string               `json:"description"`
+	Author      UserResponse         `json:"author"`
+	PartCount   int64                `json:"part_count"`
+	Parts       []SeriesPartResponse `json:"parts,omitempty"`
+	CreatedAt   string               `json:"created_at"`
+	UpdatedAt   string               `json:"updated_at"`
+}
+
+type SeriesPartResponse struct {
+	Position  int    `json:"position"`
+	ArticleID string `json:"article_id"`
+	Title     string `json:"title"`
+	Slug      string `json:"slug"`
+	Published bool   `json:"published"`
+}
+
+// SeriesNavigation is included in an article response when the article is
+// part of a series
+type SeriesNavigation struct {
+	ID       string              `json:"id"`
+	Title    string              `json:"title"`
+	Position int                 `json:"position"`
+	Total    int                 `json:"total"`
+	Previous *SeriesPartResponse `json:"previous,omitempty"`
+	Next     *SeriesPartResponse `json:"next,omitempty"`
+}
+
+// visibleSeriesParts returns the parts a viewer may see, renumbered from 1.
+// The author sees every part; everyone else only sees live articles that are
+// not hidden by moderation and would appear in their listings, so unlisted
+// and password-protected parts are not advertised. The part for currentID,
+// if given, is always kept.
+func visibleSeriesParts(series *models.Series, viewerID, currentID string) []SeriesPartResponse {
+	now := time.Now()
+	parts := []SeriesPartResponse{}
+	for i := range series.Parts {
+		article := &series.Parts[i].Article
+		// Deleted articles are not preloaded
+		if article.ID == "" {
+			continue
+		}
+		visible := article.IsLive(now) && !article.IsHidden() && article.IsListedFor(viewerID != "")
+		if series.AuthorID != viewerID && !visible && article.ID != currentID {
+			continue
+		}
+		parts = append(parts, SeriesPartResponse{
+			Position:  len(parts) + 1,
+			ArticleID: article.ID,
+			Title:     article.Title,
+			Slug:      article.Slug,
+			Published: article.IsPublished(),
+		})
+	}
+	return parts
+}
+
+func newSeriesResponse(series *models.Series) SeriesResponse {
+	return SeriesResponse{
+		ID:          series.ID,
+		Title:       series.Title,
+		Description: series.Description,
+		Author: UserResponse{
+			ID:   series.Author.ID,
+			Name: series.Author.Name,
+		},
+		PartCount: series.PartCount,
+		CreatedAt: series.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt: series.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func newSeriesDetailResponse(series *models.Series, viewerID string) SeriesResponse {
+	response := newSeriesResponse(series)
+	response.Parts = visibleSeriesParts(series, viewerID, "")
+	response.PartCount = int64(len(response.Parts))
+	return response
+}
+
+// seriesNavigation builds the previous/next links for an article, or returns
+// nil if the article is not part of a series
+func seriesNavigation(ctx *gin.Context, articleID string) (*SeriesNavigation, error) {
+	series, err := database.GetSeriesForArticle(ctx, articleID)
+	if err != nil || series == nil {
+		return nil, err
+	}
+
+	parts := visibleSeriesParts(series, middleware.GetUserID(ctx), articleID)
+	for i := range parts {
+		if parts[i].ArticleID != articleID {
+			continue
+		}
+		nav := &SeriesNavigation{
+			ID:       series.ID,
+			Title:    series.Title,
+			Position: parts[i].Position,
+			Total:    len(parts),
+		}
+		if i > 0 {
+			nav.Previous = &parts[i-1]
+		}
+		if i < len(parts)-1 {
+			nav.Next = &parts[i+1]
+		}
+		return nav, nil
+	}
+	return nil, nil
+}
+
+// loadOwnedSeries fetches the series in the URL and checks that the caller is
+// its author
+func loadOwnedSeries(ctx *gin.Context) (*models.Series, bool) {
+	series, err := database.GetSeriesByID(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Series not found"})
+		return nil, false
+	}
+	if series.AuthorID != middleware.GetUserID(ctx) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to modify this series"})
+		return nil, false
+	}
+	return series, true
+}
+
+// ListMySeries returns the caller's series
+func (h *SeriesHandler) ListMySeries(ctx *gin.Context) {
+	series, err := database.ListSeries(ctx, middleware.GetUserID(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve series: " + err.Error()})
+		return
+	}
+
+	response := make([]SeriesResponse, len(series))
+	for i := range series {
+		response[i] = newSeriesResponse(&series[i])
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"series": response,
+	})
+}
+
+// CreateSeries creates an empty series owned by the caller
+func (h *SeriesHandler) CreateSeries(ctx *gin.Context) {
+	var req SeriesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	series, err := database.CreateSeries(ctx, &models.Series{
+		Title:       req.Title,
+		Description: req.Description,
+		AuthorID:    middleware.GetUserID(ctx),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create series: " + err.Error()})
+		return
+	}
+
+	created, err := database.GetSeriesByID(ctx, series.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve created series"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newSeriesDetailResponse(created, created.AuthorID))
+}
+
+// GetSeries returns a series with its parts in order
+func (h *SeriesHandler) GetSeries(ctx *gin.Context) {
+	series, err := database.GetSeriesByID(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Series not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newSeriesDetailResponse(series, middleware.GetUserID(ctx)))
+}
+
+// UpdateSeries changes a series' title and description
+func (h *SeriesHandler) UpdateSeries(ctx *gin.Context) {
+	series, ok := loadOwnedSeries(ctx)
+	if !ok {
+		return
+	}
+
+	var req SeriesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	series.Title = req.Title
+	series.Description = req.Description
+
+	if err := database.UpdateSeries(ctx, series); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update series: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newSeriesDetailResponse(series, series.AuthorID))
+}
+
+// DeleteSeries removes a series, leaving its articles in place
+func (h *SeriesHandler) DeleteSeries(ctx *gin.Context) {
+	series, ok := loadOwnedSeries(ctx)
+	if !ok {
+		return
+	}
+
+	if err := database.DeleteSeries(ctx, series.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete series: " + err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// AddPart appends one of the author's articles to the series
+func (h *SeriesHandler) AddPart(ctx *gin.Context) {
+	series, ok := loadOwnedSeries(ctx)
+	if !ok {
+		return
+	}
+
+	var req AddSeriesPartRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	article, err := database.GetArticleByID(ctx, req.ArticleID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+	if !article.HasAccess(series.AuthorID, models.CollaboratorOwner) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Only articles you own can be added to a series"})
+		return
+	}
+
+	_, err = database.AddSeriesPart(ctx, series.ID, article.ID)
+	if errors.Is(err, database.ErrArticleInSeries) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Article already belongs to a series"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add article: " + err.Error()})
+		return
+	}
+
+	h.respondWithSeries(ctx, series.ID, http.StatusCreated)
+}
+
+// RemovePart takes an article out of the series
+func (h *SeriesHandler) RemovePart(ctx *gin.Context) {
+	series, ok := loadOwnedSeries(ctx)
+	if !ok {
+		return
+	}
+
+	if err := database.RemoveSeriesPart(ctx, series.ID, ctx.Param("articleId")); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article is not part of this series"})
+		return
+	}
+
+	h.respondWithSeries(ctx, series.ID, http.StatusOK)
+}
+
+// ReorderParts sets the order of every article in the series
+func (h *SeriesHandler) ReorderParts(ctx *gin.Context) {
+	series, ok := loadOwnedSeries(ctx)
+	if !ok {
+		return
+	}
+
+	var req ReorderSeriesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	err := database.ReorderSeriesParts(ctx, series.ID, req.ArticleIDs)
+	if errors.Is(err, database.ErrInvalidOrder) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "article_ids must list every article in the series exactly once"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder series: " + err.Error()})
+		return
+	}
+
+	h.respondWithSeries(ctx, series.ID, http.StatusOK)
+}
+
+// respondWithSeries reloads a series after a change and writes it out
+func (h *SeriesHandler) respondWithSeries(ctx *gin.Context, id string, status int) {
+	series, err := database.GetSeriesByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve series"})
+		return
+	}
+
+	ctx.JSON(status, newSeriesDetailResponse(series, series.AuthorID))
+}
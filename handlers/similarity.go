@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"Praiseson6065/ocrolus-be/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SimilarArticleResponse is an article resembling another one, with the
+// estimated percentage of text they share
+type SimilarArticleResponse struct {
+	ArticleResponse
+	Similarity float64 `json:"similarity"`
+}
+
+// GetSimilarContent lists the articles whose content resembles an article's,
+// most similar first. Only editors and moderators may compare articles.
+// ?min sets the lowest similarity in percent, ?limit the number returned.
+func (h *ArticleHandler) GetSimilarContent(ctx *gin.Context) {
+	article, user, ok := loadWorkflowContext(ctx)
+	if !ok {
+		return
+	}
+
+	if !user.IsEditor() && !user.IsModerator() {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to compare articles"})
+		return
+	}
+
+	minPercent, err := strconv.Atoi(ctx.DefaultQuery("min", "50"))
+	if err != nil || minPercent < 0 || minPercent > 100 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "min must be a percentage between 0 and 100"})
+		return
+	}
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	similar, err := database.FindSimilarArticles(ctx, article, float64(minPercent)/100, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find similar articles: " + err.Error()})
+		return
+	}
+
+	response := make([]SimilarArticleResponse, len(similar))
+	for i := range similar {
+		response[i] = SimilarArticleResponse{
+			ArticleResponse: articleResponseFor(ctx, &similar[i].Article),
+			Similarity:      math.Round(similar[i].Similarity * 100),
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"articles": response,
+		"count":    len(response),
+	})
+}
@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"Praiseson6065/ocrolus-be/database"
+	"Praiseson6065/ocrolus-be/middleware"
+	"Praiseson6065/ocrolus-be/models"
+	"Praiseson6065/ocrolus-be/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SuggestionHandler struct{}
+
+// Omitted fields are left as they are in the article
+type CreateSuggestionRequest struct {
+	Title   *string `json:"title"`
+	Content *string `json:"content"`
+	Comment string  `json:"comment"`
+}
+
+type ReviewSuggestionRequest struct {
+	Comment string `json:"comment"`
+}
+
+type SuggestionResponse struct {
+	ID            string          `json:"id"`
+	Status        string          `json:"status"`
+	Comment       string          `json:"comment"`
+	Author        UserResponse    `json:"author"`
+	Title         string          `json:"title,omitempty"`
+	BaseTitle     string          `json:"base_title,omitempty"`
+	ContentDiff   []util.DiffLine `json:"content_diff,omitempty"`
+	Stale         bool            `json:"stale"`
+	ReviewedBy    *UserResponse   `json:"reviewed_by,omitempty"`
+	ReviewComment string          `json:"review_comment,omitempty"`
+	ReviewedAt    string          `json:"reviewed_at,omitempty"`
+	CreatedAt     string          `json:"created_at"`
+}
+
+// maxSuggestionCommentLength bounds the note attached to a suggestion
+const maxSuggestionCommentLength = 2000
+
+// newSuggestionResponse maps a suggestion to its API representation. The
+// content diff is only computed when requested since it can be large.
+func newSuggestionResponse(suggestion *models.ArticleSuggestion, article *models.Article, withDiff bool) SuggestionResponse {
+	response := SuggestionResponse{
+		ID:      suggestion.ID,
+		Status:  suggestion.Status,
+		Comment: suggestion.Comment,
+		Author: UserResponse{
+			ID:   suggestion.Author.ID,
+			Name: suggestion.Author.Name,
+		},
+		Stale:         suggestion.Status == models.SuggestionPending && suggestion.IsStale(article),
+		ReviewComment: suggestion.ReviewComment,
+		CreatedAt:     suggestion.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if suggestion.ChangesTitle() {
+		response.Title = suggestion.Title
+		response.BaseTitle = suggestion.BaseTitle
+	}
+	if withDiff && suggestion.ChangesContent() {
+		response.ContentDiff = util.DiffLines(suggestion.BaseContent, suggestion.Content)
+	}
+	if suggestion.ReviewedBy != nil {
+		response.ReviewedBy = &UserResponse{
+			ID:   suggestion.ReviewedBy.ID,
+			Name: suggestion.ReviewedBy.Name,
+		}
+	}
+	if suggestion.ReviewedAt != nil {
+		response.ReviewedAt = suggestion.ReviewedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return response
+}
+
+// loadArticleSuggestion fetches the article and suggestion in the URL. The
+// suggestion is only visible to its author and to the article's editors.
+func loadArticleSuggestion(ctx *gin.Context) (*models.Article, *models.ArticleSuggestion, bool) {
+	article, err := database.GetArticleByID(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return nil, nil, false
+	}
+
+	suggestion, err := database.GetSuggestionByID(ctx, article.ID, ctx.Param("suggestionId"))
+	userID := middleware.GetUserID(ctx)
+	if err != nil || (suggestion.AuthorID != userID && !article.HasAccess(userID, models.CollaboratorEditor)) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Suggestion not found"})
+		return nil, nil, false
+	}
+
+	return article, suggestion, true
+}
+
+// CreateSuggestion proposes a change to a published article's title or content
+func (h *SuggestionHandler) CreateSuggestion(ctx *gin.Context) {
+	article, err := database.GetArticleByID(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+	if !checkArticleVisibility(ctx, article) {
+		return
+	}
+	if !article.IsLive(time.Now()) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Edits can only be suggested on published articles"})
+		return
+	}
+
+	var req CreateSuggestionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if len(req.Comment) > maxSuggestionCommentLength {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Comment is too long"})
+		return
+	}
+
+	suggestion := &models.ArticleSuggestion{
+		ArticleID:   article.ID,
+		AuthorID:    middleware.GetUserID(ctx),
+		BaseTitle:   article.Title,
+		BaseContent: article.Content,
+		Title:       article.Title,
+		Content:     article.Content,
+		Comment:     req.Comment,
+	}
+	if req.Title != nil {
+		if *req.Title == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Title cannot be empty"})
+			return
+		}
+		suggestion.Title = *req.Title
+	}
+	if req.Content != nil {
+		// Block articles derive their text from the blocks
+		if article.Format == models.ContentFormatBlocks {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Content edits cannot be suggested on block-based articles"})
+			return
+		}
+		if !validateContent(ctx, article.Format, *req.Content, nil) {
+			return
+		}
+		suggestion.Content = *req.Content
+	}
+	if !suggestion.ChangesTitle() && !suggestion.ChangesContent() {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "The suggestion does not change anything"})
+		return
+	}
+
+	created, err := database.CreateSuggestion(ctx, suggestion)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create suggestion: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newSuggestionResponse(created, article, true))
+}
+
+// ListSuggestions returns suggestions on an article. Editors of the article
+// see every suggestion; other users only see their own.
+func (h *SuggestionHandler) ListSuggestions(ctx *gin.Context) {
+	article, err := database.GetArticleByID(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(ctx.DefaultQuery("pageSize", "20"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	userID := middleware.GetUserID(ctx)
+	opts := database.SuggestionListOptions{Status: ctx.Query("status")}
+	if !article.HasAccess(userID, models.CollaboratorEditor) {
+		opts.AuthorID = userID
+	}
+
+	suggestions, total, err := database.ListSuggestions(ctx, article.ID, page, pageSize, opts)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve suggestions: " + err.Error()})
+		return
+	}
+
+	response := make([]SuggestionResponse, len(suggestions))
+	for i := range suggestions {
+		response[i] = newSuggestionResponse(&suggestions[i], article, false)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"suggestions": response,
+		"totalCount":  total,
+		"currentPage": page,
+		"pageSize":    pageSize,
+	})
+}
+
+// GetSuggestion returns a suggestion with the diff of its content change
+func (h *SuggestionHandler) GetSuggestion(ctx *gin.Context) {
+	article, suggestion, ok := loadArticleSuggestion(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newSuggestionResponse(suggestion, article, true))
+}
+
+// AcceptSuggestion applies a suggestion to the article. Suggestions whose
+// base text has changed since they were made must be resubmitted.
+func (h *SuggestionHandler) AcceptSuggestion(ctx *gin.Context) {
+	article, suggestion, ok := loadArticleSuggestion(ctx)
+	if !ok {
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	if !article.HasAccess(userID, models.CollaboratorEditor) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to accept this suggestion"})
+		return
+	}
+
+	var req ReviewSuggestionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && ctx.Request.ContentLength > 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if suggestion.Status != models.SuggestionPending {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Suggestion is already " + suggestion.Status})
+		return
+	}
+	if suggestion.IsStale(article) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "The article has changed since this suggestion was made"})
+		return
+	}
+
+	if !checkEditLock(ctx, article.ID) {
+		return
+	}
+
+	// A new title regenerates the slug, as with a direct edit
+	if suggestion.ChangesTitle() {
+		article.Title = suggestion.Title
+		article.Slug = ""
+	}
+	if suggestion.ChangesContent() {
+		article.Content = suggestion.Content
+	}
+
+	updatedArticle, err := database.AcceptSuggestion(ctx, article, suggestion.ID, userID, req.Comment)
+	if errors.Is(err, database.ErrVersionConflict) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "The article has changed since this suggestion was made"})
+		return
+	}
+	if errors.Is(err, database.ErrSuggestionClosed) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Suggestion is no longer pending"})
+		return
+	}
+	if respondScreeningRejected(ctx, err) {
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply suggestion: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, articleResponseFor(ctx, updatedArticle))
+}
+
+// RejectSuggestion closes a suggestion without applying it
+func (h *SuggestionHandler) RejectSuggestion(ctx *gin.Context) {
+	article, suggestion, ok := loadArticleSuggestion(ctx)
+	if !ok {
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	if !article.HasAccess(userID, models.CollaboratorEditor) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to reject this suggestion"})
+		return
+	}
+
+	var req ReviewSuggestionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && ctx.Request.ContentLength > 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	h.closeSuggestion(ctx, article, suggestion, models.SuggestionRejected, req.Comment)
+}
+
+// WithdrawSuggestion lets the suggester take back a pending suggestion
+func (h *SuggestionHandler) WithdrawSuggestion(ctx *gin.Context) {
+	article, suggestion, ok := loadArticleSuggestion(ctx)
+	if !ok {
+		return
+	}
+
+	if suggestion.AuthorID != middleware.GetUserID(ctx) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Only the author of a suggestion can withdraw it"})
+		return
+	}
+
+	h.closeSuggestion(ctx, article, suggestion, models.SuggestionWithdrawn, "")
+}
+
+func (h *SuggestionHandler) closeSuggestion(ctx *gin.Context, article *models.Article, suggestion *models.ArticleSuggestion, status, comment string) {
+	err := database.CloseSuggestion(ctx, suggestion.ID, status, middleware.GetUserID(ctx), comment)
+	if errors.Is(err, database.ErrSuggestionClosed) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Suggestion is already " + suggestion.Status})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update suggestion: " + err.Error()})
+		return
+	}
+
+	updated, err := database.GetSuggestionByID(ctx, article.ID, suggestion.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve suggestion"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newSuggestionResponse(updated, article, false))
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"Praiseson6065/ocrolus-be/database"
+	"Praiseson6065/ocrolus-be/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SuggestTagsRequest is a draft to extract keywords from. Tags already
+// chosen for it are not suggested again.
+type SuggestTagsRequest struct {
+	Title   string                `json:"title"`
+	Content string                `json:"content"`
+	Format  string                `json:"format"`
+	Blocks  *models.BlockDocument `json:"blocks"`
+	Tags    []string              `json:"tags"`
+}
+
+// TagSuggestionResponse is a proposed tag. Existing suggestions name a tag
+// other articles already use.
+type TagSuggestionResponse struct {
+	Tag      string  `json:"tag"`
+	Slug     string  `json:"slug,omitempty"`
+	Score    float64 `json:"score"`
+	Existing bool    `json:"existing"`
+}
+
+// SuggestTags ranks the keywords of a draft body as tag suggestions, best
+// first. ?limit sets the number of suggestions.
+func (h *ArticleHandler) SuggestTags(ctx *gin.Context) {
+	var req SuggestTagsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if req.Format == "" {
+		req.Format = models.ContentFormatPlaintext
+		if req.Blocks != nil {
+			req.Format = models.ContentFormatBlocks
+		}
+	}
+	if !validateContent(ctx, req.Format, req.Content, req.Blocks) {
+		return
+	}
+
+	tags, ok := articleTags(ctx, req.Tags)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 25 {
+		limit = 10
+	}
+
+	draft := &models.Article{
+		Title:   req.Title,
+		Content: req.Content,
+		Format:  req.Format,
+		Blocks:  req.Blocks,
+		Tags:    tags,
+	}
+	suggestions, err := database.SuggestTags(ctx, draft, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to suggest tags: " + err.Error()})
+		return
+	}
+
+	response := make([]TagSuggestionResponse, len(suggestions))
+	for i, suggestion := range suggestions {
+		response[i] = TagSuggestionResponse{
+			Tag:   suggestion.Keyword,
+			Score: math.Round(suggestion.Score*10000) / 10000,
+		}
+		if suggestion.Tag != nil {
+			response[i].Tag = suggestion.Tag.Name
+			response[i].Slug = suggestion.Tag.Slug
+			response[i].Existing = true
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"suggestions": response})
+}
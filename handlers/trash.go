@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"Praiseson6065/ocrolus-be/config"
+	"Praiseson6065/ocrolus-be/database"
+	"Praiseson6065/ocrolus-be/middleware"
+	"Praiseson6065/ocrolus-be/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TrashedArticleResponse is an article in the trash, with when it was
+// deleted and when it will be purged automatically
+type TrashedArticleResponse struct {
+	ArticleResponse
+	DeletedAt string `json:"deleted_at"`
+	PurgeAt   string `json:"purge_at,omitempty"`
+}
+
+func newTrashedArticleResponse(ctx *gin.Context, article *models.Article) TrashedArticleResponse {
+	response := TrashedArticleResponse{
+		ArticleResponse: articleResponseFor(ctx, article),
+		DeletedAt:       article.DeletedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if retention := config.Config.Trash.Retention; retention > 0 {
+		purgeAt := article.DeletedAt.Time.Add(time.Duration(retention) * 24 * time.Hour)
+		response.PurgeAt = purgeAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return response
+}
+
+// ListTrash returns the deleted articles the caller owns
+func (h *ArticleHandler) ListTrash(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	articles, total, err := database.ListTrashedArticles(ctx, userID, page, pageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve trash: " + err.Error()})
+		return
+	}
+
+	responseArticles := make([]TrashedArticleResponse, len(articles))
+	for i := range articles {
+		responseArticles[i] = newTrashedArticleResponse(ctx, &articles[i])
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"articles":    responseArticles,
+		"totalCount":  total,
+		"currentPage": page,
+		"pageSize":    pageSize,
+	})
+}
+
+// loadTrashedArticle fetches a deleted article the caller owns, writing an
+// error response if there is none
+func loadTrashedArticle(ctx *gin.Context) (*models.Article, bool) {
+	article, err := database.GetTrashedArticleByID(ctx, ctx.Param("id"))
+	if errors.Is(err, database.ErrNotInTrash) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found in trash"})
+		return nil, false
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve article: " + err.Error()})
+		return nil, false
+	}
+
+	if !article.HasAccess(middleware.GetUserID(ctx), models.CollaboratorOwner) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Only owners can restore or purge this article"})
+		return nil, false
+	}
+	return article, true
+}
+
+// RestoreArticle moves a deleted article out of the trash
+func (h *ArticleHandler) RestoreArticle(ctx *gin.Context) {
+	article, ok := loadTrashedArticle(ctx)
+	if !ok {
+		return
+	}
+
+	err := database.RestoreArticle(ctx, article.ID)
+	if errors.Is(err, database.ErrNotInTrash) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found in trash"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore article: " + err.Error()})
+		return
+	}
+
+	restored, err := database.GetArticleByID(ctx, article.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve article: " + err.Error()})
+		return
+	}
+
+	setETag(ctx, restored.Version)
+	ctx.JSON(http.StatusOK, articleResponseFor(ctx, restored))
+}
+
+// PurgeArticle permanently removes a deleted article and everything attached
+// to it
+func (h *ArticleHandler) PurgeArticle(ctx *gin.Context) {
+	article, ok := loadTrashedArticle(ctx)
+	if !ok {
+		return
+	}
+
+	err := database.PurgeArticle(ctx, article.ID)
+	if errors.Is(err, database.ErrNotInTrash) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found in trash"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to purge article: " + err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"net/mail"
 
 	"Praiseson6065/ocrolus-be/database"
 	"Praiseson6065/ocrolus-be/middleware"
+	"Praiseson6065/ocrolus-be/util"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,10 +20,30 @@ type CreateUserRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// UserPatchDocument holds the profile fields that PATCH requests can change.
+// The password is write-only and always reads as empty.
+type UserPatchDocument struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
 type UserResponse struct {
 	ID    string `json:"id"`
 	Name  string `json:"name"`
-	Email string `json:"email"`
+	Email string `json:"email,omitempty"`
+	Role  string `json:"role,omitempty"`
+}
+
+// respondUserConflict reports that the caller's profile changed while a
+// request was being processed, along with its current version
+func respondUserConflict(ctx *gin.Context, id string) {
+	current, err := database.GetUserByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	respondPreconditionFailed(ctx, current.Version)
 }
 
 func (h *UserHandler) GetUser(ctx *gin.Context) {
@@ -36,8 +59,10 @@ func (h *UserHandler) GetUser(ctx *gin.Context) {
 		ID:    user.ID,
 		Name:  user.Name,
 		Email: user.Email,
+		Role:  user.Role,
 	}
 
+	setETag(ctx, user.Version)
 	ctx.JSON(http.StatusOK, response)
 }
 
@@ -50,6 +75,10 @@ func (h *UserHandler) UpdateUser(ctx *gin.Context) {
 		return
 	}
 
+	if !checkIfMatch(ctx, existingUser.Version) {
+		return
+	}
+
 	var req CreateUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
@@ -76,7 +105,10 @@ func (h *UserHandler) UpdateUser(ctx *gin.Context) {
 	}
 
 	updatedUser, err := database.UpdateUser(ctx, existingUser)
-
+	if errors.Is(err, database.ErrVersionConflict) {
+		respondUserConflict(ctx, id)
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user: " + err.Error()})
 		return
@@ -86,15 +118,106 @@ func (h *UserHandler) UpdateUser(ctx *gin.Context) {
 		ID:    updatedUser.ID,
 		Name:  updatedUser.Name,
 		Email: updatedUser.Email,
+		Role:  updatedUser.Role,
+	}
+
+	setETag(ctx, updatedUser.Version)
+	ctx.JSON(http.StatusOK, response)
+}
+
+// PatchUser applies a merge patch or JSON patch to the caller's profile. Only
+// the fields the patch changes are validated and written.
+func (h *UserHandler) PatchUser(ctx *gin.Context) {
+	id := middleware.GetUserID(ctx)
+	existingUser, err := database.GetUserByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if !checkIfMatch(ctx, existingUser.Version) {
+		return
+	}
+
+	document := UserPatchDocument{
+		Name:  existingUser.Name,
+		Email: existingUser.Email,
+	}
+	var patched UserPatchDocument
+	changed, ok := applyPatchRequest(ctx, document, &patched)
+	if !ok {
+		return
+	}
+
+	var columns []string
+	if changed["name"] {
+		if patched.Name == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Name cannot be empty"})
+			return
+		}
+		existingUser.Name = patched.Name
+		columns = append(columns, "name")
+	}
+	if changed["email"] {
+		if _, err := mail.ParseAddress(patched.Email); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid email address"})
+			return
+		}
+		if userWithEmail, _ := database.GetUserByEmail(ctx, patched.Email); userWithEmail != nil {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "Email is already in use"})
+			return
+		}
+		existingUser.Email = patched.Email
+		columns = append(columns, "email")
+	}
+	if changed["password"] {
+		existingUser.Password = util.HashAndSalt(patched.Password)
+		columns = append(columns, "password")
+	}
+
+	updatedUser := *existingUser
+	if len(columns) > 0 {
+		updatedUser, err = database.UpdateUserColumns(ctx, existingUser, columns)
+		if errors.Is(err, database.ErrVersionConflict) {
+			respondUserConflict(ctx, id)
+			return
+		}
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user: " + err.Error()})
+			return
+		}
+	}
+
+	response := UserResponse{
+		ID:    updatedUser.ID,
+		Name:  updatedUser.Name,
+		Email: updatedUser.Email,
+		Role:  updatedUser.Role,
 	}
 
+	setETag(ctx, updatedUser.Version)
 	ctx.JSON(http.StatusOK, response)
 }
 
 func (h *UserHandler) DeleteUser(ctx *gin.Context) {
 	id := middleware.GetUserID(ctx)
 
-	if err := database.DeleteUser(ctx, id); err != nil {
+	user, err := database.GetUserByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if !checkIfMatch(ctx, user.Version) {
+		return
+	}
+
+	err = database.DeleteUser(ctx, id, user.Version)
+	if errors.Is(err, database.ErrVersionConflict) {
+		respondUserConflict(ctx, id)
+		return
+	}
+	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user: " + err.Error()})
 		return
 	}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+
+	"Praiseson6065/ocrolus-be/database"
+	"Praiseson6065/ocrolus-be/middleware"
+	"Praiseson6065/ocrolus-be/models"
+	"Praiseson6065/ocrolus-be/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// minArticlePasswordLength is the shortest password an article can be
+// protected with
+const minArticlePasswordLength = 6
+
+// articleAccessHeader carries the access grant for a password-protected
+// article; the access_token query parameter can be used for links
+const articleAccessHeader = "X-Article-Access"
+
+type ArticleAccessRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// applyVisibility validates a visibility change and the password that goes
+// with it and sets them on the article, writing a 400 response if they are
+// not acceptable. Empty values keep the current visibility and password.
+func applyVisibility(ctx *gin.Context, article *models.Article, visibility, password string) bool {
+	if visibility == "" {
+		visibility = article.Visibility
+	}
+	if visibility == "" {
+		visibility = models.ArticleVisibilityPublic
+	}
+	if !models.IsValidArticleVisibility(visibility) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Visibility must be one of public, unlisted, members or password"})
+		return false
+	}
+
+	if visibility == models.ArticleVisibilityPassword {
+		switch {
+		case password != "" && len([]rune(password)) < minArticlePasswordLength:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Article passwords must be at least " + strconv.Itoa(minArticlePasswordLength) + " characters long"})
+			return false
+		case password != "":
+			article.PasswordHash = util.HashAndSalt(password)
+		case article.PasswordHash == "":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "A password is required for password-protected articles"})
+			return false
+		}
+	} else {
+		if password != "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "A password can only be set on password-protected articles"})
+			return false
+		}
+		article.PasswordHash = ""
+	}
+
+	article.Visibility = visibility
+	return true
+}
+
+// articleAccessKey ties access grants to the article's current password
+func articleAccessKey(article *models.Article) string {
+	sum := sha256.Sum256([]byte(article.PasswordHash))
+	return hex.EncodeToString(sum[:8])
+}
+
+// hasArticleAccessGrant reports whether the request carries a valid access
+// grant for a password-protected article
+func hasArticleAccessGrant(ctx *gin.Context, article *models.Article) bool {
+	token := ctx.GetHeader(articleAccessHeader)
+	if token == "" {
+		token = ctx.Query("access_token")
+	}
+	if token == "" {
+		return false
+	}
+	articleID, key, err := middleware.ValidateArticleAccessToken(token)
+	return err == nil && articleID == article.ID && key == articleAccessKey(article)
+}
+
+// checkArticleVisibility enforces an article's visibility level for the
+// caller, writing a 401 response if they may not read it. Unlisted articles
+// are readable by anyone who has the link. Articles hidden pending
+// moderation are only readable by their members and by moderators, and
+// articles that are not live only by their members, their reviewer and site
+// staff; everyone else gets a 404 as if the article did not exist. Drafts
+// are shared with other readers through preview links.
+func checkArticleVisibility(ctx *gin.Context, article *models.Article) bool {
+	userID := middleware.GetUserID(ctx)
+	if article.HasAccess(userID, models.CollaboratorViewer) {
+		return true
+	}
+
+	if article.IsHidden() && !isModerator(ctx) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "This article is hidden pending moderation"})
+		return false
+	}
+
+	if !article.IsLive(time.Now()) && !canReadUnpublished(ctx, article) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return false
+	}
+
+	switch article.Visibility {
+	case models.ArticleVisibilityMembers:
+		if userID == "" {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Sign in to read this article"})
+			return false
+		}
+	case models.ArticleVisibilityPassword:
+		if !hasArticleAccessGrant(ctx, article) {
+			ctx.JSON(http.StatusUnauthorized, gin.H{
+				"error":            "This article is password protected",
+				"passwordRequired": true,
+			})
+			return false
+		}
+	}
+	return true
+}
+
+// canReadUnpublished reports whether the caller may read an article that is
+// not live without being one of its members: its assigned reviewer,
+// site editors and moderators
+func canReadUnpublished(ctx *gin.Context, article *models.Article) bool {
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		return false
+	}
+	if article.ReviewerID != nil && *article.ReviewerID == userID {
+		return true
+	}
+	user, err := middleware.GetUser(ctx)
+	return err == nil && (user.IsEditor() || user.IsModerator())
+}
+
+// listedVisibilities returns the visibility levels the caller sees in
+// public listings
+func listedVisibilities(ctx *gin.Context) []string {
+	return models.ListedVisibilities(middleware.GetUserID(ctx) != "")
+}
+
+// GrantArticleAccess exchanges the password of a password-protected article
+// for a short-lived access grant
+func (h *ArticleHandler) GrantArticleAccess(ctx *gin.Context) {
+	article, err := database.GetArticleByID(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+
+	if article.Visibility != models.ArticleVisibilityPassword {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "This article is not password protected"})
+		return
+	}
+
+	var req ArticleAccessRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if !util.ComparePasswords(article.PasswordHash, req.Password) {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Incorrect password"})
+		return
+	}
+
+	token, expiresAt, err := middleware.GenerateArticleAccessToken(article.ID, articleAccessKey(article))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create access grant: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"accessToken": token,
+		"expiresAt":   expiresAt.Format("2006-01-02T15:04:05Z07:00"),
+	})
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"Praiseson6065/ocrolus-be/database"
+	"Praiseson6065/ocrolus-be/middleware"
+	"Praiseson6065/ocrolus-be/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TransitionArticleRequest struct {
+	State      string  `json:"state" binding:"required"`
+	Comment    string  `json:"comment"`
+	ReviewerID *string `json:"reviewer_id"`
+}
+
+type AssignReviewerRequest struct {
+	ReviewerID *string `json:"reviewer_id"`
+}
+
+type TransitionResponse struct {
+	ID        string        `json:"id"`
+	FromState string        `json:"from_state"`
+	ToState   string        `json:"to_state"`
+	Comment   string        `json:"comment"`
+	Actor     *UserResponse `json:"actor,omitempty"`
+	CreatedAt string        `json:"created_at"`
+}
+
+// workflowRoles returns the workflow roles a user holds on an article
+func workflowRoles(user *models.User, article *models.Article) []string {
+	var roles []string
+	// Collaborators with edit access act as authors
+	if article.HasAccess(user.ID, models.CollaboratorEditor) {
+		roles = append(roles, models.WorkflowAuthor)
+	}
+	if article.ReviewerID != nil && *article.ReviewerID == user.ID {
+		roles = append(roles, models.WorkflowReviewer)
+	}
+	if user.IsEditor() {
+		roles = append(roles, models.WorkflowEditor)
+	}
+	return roles
+}
+
+// validateReviewer checks that a reviewer exists and is not one of the
+// article's authors
+func validateReviewer(ctx *gin.Context, article *models.Article, reviewerID string) bool {
+	if article.HasAccess(reviewerID, models.CollaboratorEditor) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Authors cannot review their own articles"})
+		return false
+	}
+	if _, err := database.GetUserByID(ctx, reviewerID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Reviewer not found"})
+		return false
+	}
+	return true
+}
+
+// loadWorkflowContext fetches the article and the acting user, writing an
+// error response and returning false if either cannot be loaded
+func loadWorkflowContext(ctx *gin.Context) (*models.Article, *models.User, bool) {
+	article, err := database.GetArticleByID(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return nil, nil, false
+	}
+
+	user, err := middleware.GetUser(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return nil, nil, false
+	}
+
+	return article, user, true
+}
+
+// TransitionArticle moves an article to a new editorial state
+func (h *ArticleHandler) TransitionArticle(ctx *gin.Context) {
+	article, user, ok := loadWorkflowContext(ctx)
+	if !ok {
+		return
+	}
+
+	var req TransitionArticleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if !models.IsValidArticleState(req.State) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid state: " + req.State})
+		return
+	}
+
+	if !models.CanTransitionArticle(article.State, req.State, workflowRoles(user, article)) {
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"error": "You cannot move this article from " + article.State + " to " + req.State,
+		})
+		return
+	}
+
+	// A reviewer can be assigned as part of submitting for review
+	if req.ReviewerID != nil && !validateReviewer(ctx, article, *req.ReviewerID) {
+		return
+	}
+
+	if err := database.TransitionArticle(ctx, article, req.State, user.ID, req.Comment, req.ReviewerID); err != nil {
+		if errors.Is(err, database.ErrStateConflict) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "Article state changed, please reload"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to transition article: " + err.Error()})
+		return
+	}
+
+	updatedArticle, err := database.GetArticleByID(ctx, article.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve article: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, articleResponseFor(ctx, updatedArticle))
+}
+
+// AssignReviewer sets or clears the reviewer of an article
+func (h *ArticleHandler) AssignReviewer(ctx *gin.Context) {
+	article, user, ok := loadWorkflowContext(ctx)
+	if !ok {
+		return
+	}
+
+	if !article.HasAccess(user.ID, models.CollaboratorEditor) && !user.IsEditor() {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to assign a reviewer"})
+		return
+	}
+
+	var req AssignReviewerRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if req.ReviewerID != nil && !validateReviewer(ctx, article, *req.ReviewerID) {
+		return
+	}
+
+	if err := database.AssignReviewer(ctx, article.ID, req.ReviewerID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign reviewer: " + err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListTransitions returns the editorial history of an article, including
+// reviewer comments
+func (h *ArticleHandler) ListTransitions(ctx *gin.Context) {
+	article, user, ok := loadWorkflowContext(ctx)
+	if !ok {
+		return
+	}
+
+	if len(workflowRoles(user, article)) == 0 {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to view this article's history"})
+		return
+	}
+
+	transitions, err := database.ListArticleTransitions(ctx, article.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve transitions: " + err.Error()})
+		return
+	}
+
+	response := make([]TransitionResponse, len(transitions))
+	for i, transition := range transitions {
+		response[i] = TransitionResponse{
+			ID:        transition.ID,
+			FromState: transition.FromState,
+			ToState:   transition.ToState,
+			Comment:   transition.Comment,
+			CreatedAt: transition.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+		if transition.Actor != nil {
+			response[i].Actor = &UserResponse{
+				ID:   transition.Actor.ID,
+				Name: transition.Actor.Name,
+			}
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"transitions": response,
+	})
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"Praiseson6065/ocrolus-be/config"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// ArticleAccessClaims grant read access to one password-protected article.
+// Key is derived from the article's password so that changing the password
+// revokes outstanding grants.
+type ArticleAccessClaims struct {
+	ArticleID string `json:"articleId"`
+	Key       string `json:"key"`
+	jwt.StandardClaims
+}
+
+// articleAccessSigningKey keeps access grants from being accepted as login
+// tokens and the other way round
+func articleAccessSigningKey() []byte {
+	return []byte(config.Config.JWT.Secret + ":article-access")
+}
+
+// GenerateArticleAccessToken issues a short-lived access grant for an article
+func GenerateArticleAccessToken(articleID, key string) (string, time.Time, error) {
+	expiresAt := time.Now().Add(time.Duration(config.Config.Visibility.AccessGrantTTL) * time.Minute)
+
+	claims := ArticleAccessClaims{
+		articleID,
+		key,
+		jwt.StandardClaims{
+			ExpiresAt: expiresAt.Unix(),
+			IssuedAt:  jwt.TimeFunc().Unix(),
+			Issuer:    "ocrolus",
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString(articleAccessSigningKey())
+	return tokenString, expiresAt, err
+}
+
+// ValidateArticleAccessToken checks an access grant and returns the article
+// and key it was issued for
+func ValidateArticleAccessToken(encodedToken string) (string, string, error) {
+	claims := &ArticleAccessClaims{}
+
+	_, err := jwt.ParseWithClaims(encodedToken, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, isValid := t.Method.(*jwt.SigningMethodHMAC); !isValid {
+			return nil, fmt.Errorf("invalid token %s", t.Header["alg"])
+		}
+		return articleAccessSigningKey(), nil
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return claims.ArticleID, claims.Key, nil
+}
+
+// PreviewClaims identify a draft preview link. The link itself is looked up
+// on every use so that it can be revoked.
+type PreviewClaims struct {
+	LinkID string `json:"linkId"`
+	jwt.StandardClaims
+}
+
+func previewSigningKey() []byte {
+	return []byte(config.Config.JWT.Secret + ":article-preview")
+}
+
+// GeneratePreviewToken signs the token for a preview link
+func GeneratePreviewToken(linkID string, expiresAt time.Time) (string, error) {
+	claims := PreviewClaims{
+		linkID,
+		jwt.StandardClaims{
+			ExpiresAt: expiresAt.Unix(),
+			IssuedAt:  jwt.TimeFunc().Unix(),
+			Issuer:    "ocrolus",
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(previewSigningKey())
+}
+
+// ValidatePreviewToken checks the signature and expiry of a preview token
+// and returns the link it was issued for
+func ValidatePreviewToken(encodedToken string) (string, error) {
+	claims := &PreviewClaims{}
+
+	_, err := jwt.ParseWithClaims(encodedToken, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, isValid := t.Method.(*jwt.SigningMethodHMAC); !isValid {
+			return nil, fmt.Errorf("invalid token %s", t.Header["alg"])
+		}
+		return previewSigningKey(), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return claims.LinkID, nil
+}
@@ -3,6 +3,10 @@ package middleware
 import (
 	"net/http"
 	"strings"
+	"time"
+
+	"Praiseson6065/ocrolus-be/database"
+	"Praiseson6065/ocrolus-be/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -33,7 +37,11 @@ func Authenicator() gin.HandlerFunc {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid Token"})
 		}
 
-		ctx.Set("userId", userId)
+		// Suspended accounts cannot use authenticated routes until the suspension ends
+		if !setUser(ctx, userId) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Your account has been suspended"})
+			return
+		}
 
 		ctx.Next()
 
@@ -41,6 +49,9 @@ func Authenicator() gin.HandlerFunc {
 
 }
 
+// OptionalAuthenticator identifies the caller when a valid token is sent.
+// Suspended accounts are treated as anonymous, so they can still read public
+// content but lose any access their roles would give them.
 func OptionalAuthenticator() gin.HandlerFunc {
 
 	return func(ctx *gin.Context) {
@@ -52,7 +63,7 @@ func OptionalAuthenticator() gin.HandlerFunc {
 				encodedToken := fields[1]
 				userId, err := ValidateToken(encodedToken)
 				if err == nil {
-					ctx.Set("userId", userId)
+					setUser(ctx, userId)
 				}
 			}
 		}
@@ -61,6 +72,32 @@ func OptionalAuthenticator() gin.HandlerFunc {
 
 }
 
+// userKey holds the authenticated user once it has been looked up
+const userKey = "user"
+
+// setUser records the authenticated user on the context. It returns false,
+// recording nothing, when the account is suspended.
+func setUser(ctx *gin.Context, userID string) bool {
+	user, err := database.GetUserByID(ctx, userID)
+	if err == nil && user.IsSuspended(time.Now()) {
+		return false
+	}
+	ctx.Set("userId", userID)
+	if err == nil {
+		ctx.Set(userKey, user)
+	}
+	return true
+}
+
 func GetUserID(ctx *gin.Context) string {
 	return ctx.GetString("userId")
 }
+
+// GetUser returns the authenticated user, reusing the lookup made by the
+// authenticators
+func GetUser(ctx *gin.Context) (*models.User, error) {
+	if user, ok := ctx.Get(userKey); ok {
+		return user.(*models.User), nil
+	}
+	return database.GetUserByID(ctx, GetUserID(ctx))
+}
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+// ArticleSlug keeps every slug an article has used so that old links can be
+// redirected to the current one. A slug belongs to one article forever.
+type ArticleSlug struct {
+	ID        string    `gorm:"primaryKey;<-:create" json:"id"`
+	Slug      string    `json:"slug" gorm:"uniqueIndex;not null"`
+	ArticleID string    `json:"article_id" gorm:"not null;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,71 @@
+package models
+
+// Editorial states an article moves through
+const (
+	ArticleStateDraft            = "draft"
+	ArticleStateInReview         = "in_review"
+	ArticleStateChangesRequested = "changes_requested"
+	ArticleStateApproved         = "approved"
+	ArticleStatePublished        = "published"
+	ArticleStateArchived         = "archived"
+)
+
+// Roles a user can hold relative to a single article in the workflow
+const (
+	WorkflowAuthor   = "author"
+	WorkflowReviewer = "reviewer"
+	WorkflowEditor   = "editor"
+)
+
+// articleTransitions lists, for each state, the states it may move to and
+// the workflow roles allowed to make that move.
+var articleTransitions = map[string]map[string][]string{
+	ArticleStateDraft: {
+		ArticleStateInReview:  {WorkflowAuthor, WorkflowEditor},
+		ArticleStatePublished: {WorkflowEditor},
+		ArticleStateArchived:  {WorkflowAuthor, WorkflowEditor},
+	},
+	ArticleStateInReview: {
+		ArticleStateDraft:            {WorkflowAuthor, WorkflowEditor},
+		ArticleStateChangesRequested: {WorkflowReviewer, WorkflowEditor},
+		ArticleStateApproved:         {WorkflowReviewer, WorkflowEditor},
+	},
+	ArticleStateChangesRequested: {
+		ArticleStateDraft:    {WorkflowAuthor, WorkflowEditor},
+		ArticleStateInReview: {WorkflowAuthor, WorkflowEditor},
+	},
+	ArticleStateApproved: {
+		ArticleStateDraft:     {WorkflowAuthor, WorkflowEditor},
+		ArticleStatePublished: {WorkflowAuthor, WorkflowEditor},
+	},
+	ArticleStatePublished: {
+		ArticleStateDraft:    {WorkflowAuthor, WorkflowEditor},
+		ArticleStateArchived: {WorkflowAuthor, WorkflowEditor},
+	},
+	ArticleStateArchived: {
+		ArticleStateDraft: {WorkflowAuthor, WorkflowEditor},
+	},
+}
+
+// IsValidArticleState reports whether state is a known editorial state
+func IsValidArticleState(state string) bool {
+	_, ok := articleTransitions[state]
+	return ok
+}
+
+// CanTransitionArticle reports whether a user holding any of the given
+// workflow roles may move an article from one state to another
+func CanTransitionArticle(from, to string, roles []string) bool {
+	allowed, ok := articleTransitions[from][to]
+	if !ok {
+		return false
+	}
+	for _, role := range roles {
+		for _, allowedRole := range allowed {
+			if role == allowedRole {
+				return true
+			}
+		}
+	}
+	return false
+}
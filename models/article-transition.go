@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// ArticleTransition records a single state change of an article. ActorID is
+// empty for changes made by the scheduler.
+type ArticleTransition struct {
+	ID        string    `gorm:"primaryKey;<-:create" json:"id"`
+	ArticleID string    `json:"article_id" gorm:"not null;index"`
+	FromState string    `json:"from_state" gorm:"not null"`
+	ToState   string    `json:"to_state" gorm:"not null"`
+	ActorID   *string   `json:"actor_id,omitempty"`
+	Actor     *User     `json:"actor,omitempty" gorm:"foreignKey:ActorID"`
+	Comment   string    `json:"comment" gorm:"type:text"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,46 @@
+package models
+
+// Who can read an article, on top of its editorial state. The author and
+// collaborators can always read it.
+const (
+	// ArticleVisibilityPublic articles are readable by anyone and listed
+	ArticleVisibilityPublic = "public"
+	// ArticleVisibilityUnlisted articles are readable by anyone with the
+	// link but left out of listings
+	ArticleVisibilityUnlisted = "unlisted"
+	// ArticleVisibilityMembers articles are readable and listed for any
+	// signed-in user
+	ArticleVisibilityMembers = "members"
+	// ArticleVisibilityPassword articles are readable with an access grant
+	// obtained with the article's password, and left out of listings
+	ArticleVisibilityPassword = "password"
+)
+
+// IsValidArticleVisibility reports whether visibility is a known visibility level
+func IsValidArticleVisibility(visibility string) bool {
+	switch visibility {
+	case ArticleVisibilityPublic, ArticleVisibilityUnlisted, ArticleVisibilityMembers, ArticleVisibilityPassword:
+		return true
+	}
+	return false
+}
+
+// ListedVisibilities returns the visibility levels that appear in listings
+// for a signed-in or anonymous reader
+func ListedVisibilities(authenticated bool) []string {
+	if authenticated {
+		return []string{ArticleVisibilityPublic, ArticleVisibilityMembers}
+	}
+	return []string{ArticleVisibilityPublic}
+}
+
+// IsListedFor reports whether the article appears in listings for a reader
+// who is not one of its members
+func (article *Article) IsListedFor(authenticated bool) bool {
+	for _, visibility := range ListedVisibilities(authenticated) {
+		if article.Visibility == visibility {
+			return true
+		}
+	}
+	return false
+}
@@ -6,15 +6,84 @@ import (
 	"gorm.io/gorm"
 )
 
+// Source formats for article content
+const (
+	ContentFormatMarkdown  = "markdown"
+	ContentFormatPlaintext = "plaintext"
+	ContentFormatHTML      = "html"
+	ContentFormatBlocks    = "blocks"
+)
+
 type Article struct {
 	gorm.Model
-	ID        string         `gorm:"primaryKey;<-:create" json:"id"`
-	Title     string         `json:"title" gorm:"not null"`
-	Content   string         `json:"content" gorm:"type:text;not null"`
-	AuthorID  string         `json:"author_id" gorm:"not null"`
-	Author    User           `json:"author,omitempty" gorm:"foreignKey:AuthorID"`
-	Published bool           `json:"published" gorm:"default:false"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+	ID             string                `gorm:"primaryKey;<-:create" json:"id"`
+	Title          string                `json:"title" gorm:"not null"`
+	Slug           string                `json:"slug" gorm:"uniqueIndex"`
+	Content        string                `json:"content" gorm:"type:text;not null"`
+	Format         string                `json:"format" gorm:"not null;default:plaintext"`
+	ContentHTML    string                `json:"content_html,omitempty" gorm:"type:text"`
+	Blocks         *BlockDocument        `json:"blocks,omitempty" gorm:"type:jsonb"`
+	CustomExcerpt  string                `json:"custom_excerpt,omitempty" gorm:"type:text"`
+	Excerpt        string                `json:"excerpt,omitempty" gorm:"type:text"`
+	Summary        string                `json:"summary,omitempty" gorm:"type:text"`
+	WordCount      int                   `json:"word_count" gorm:"not null;default:0"`
+	ReadingTime    int                   `json:"reading_time" gorm:"not null;default:0"`
+	Keywords       Keywords              `json:"keywords,omitempty" gorm:"type:jsonb"`
+	AuthorID       string                `json:"author_id" gorm:"not null"`
+	Author         User                  `json:"author,omitempty" gorm:"foreignKey:AuthorID"`
+	Collaborators  []ArticleCollaborator `json:"collaborators,omitempty" gorm:"foreignKey:ArticleID"`
+	Tags           []Tag                 `json:"tags,omitempty" gorm:"many2many:article_tags"`
+	State          string                `json:"state" gorm:"not null;default:draft;index"`
+	Visibility     string                `json:"visibility" gorm:"not null;default:public;index"`
+	PasswordHash   string                `json:"-"`
+	ReviewerID     *string               `json:"reviewer_id,omitempty" gorm:"index"`
+	Reviewer       *User                 `json:"reviewer,omitempty" gorm:"foreignKey:ReviewerID"`
+	Version        int64                 `json:"version" gorm:"not null;default:1"`
+	CommentsClosed bool                  `json:"comments_closed" gorm:"not null;default:false"`
+	HiddenAt       *time.Time            `json:"hidden_at,omitempty" gorm:"index"`
+	HeldAt         *time.Time            `json:"held_at,omitempty" gorm:"index"`
+	SpamTrainedAt  *time.Time            `json:"-"`
+	PublishAt      *time.Time            `json:"publish_at,omitempty" gorm:"index"`
+	UnpublishAt    *time.Time            `json:"unpublish_at,omitempty" gorm:"index"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt        `json:"deleted_at,omitempty" gorm:"index"`
+}
+
+// IsPublished reports whether the article is in the published state
+func (article *Article) IsPublished() bool {
+	return article.State == ArticleStatePublished
+}
+
+// IsLive reports whether the article is publicly readable at the given
+// time: published, or approved with a publish time that has come, and
+// neither past its unpublish time nor hidden. It matches the window public
+// listings use, so reads agree with listings while the scheduler catches up.
+func (article *Article) IsLive(now time.Time) bool {
+	if article.IsHidden() {
+		return false
+	}
+	if article.UnpublishAt != nil && !article.UnpublishAt.After(now) {
+		return false
+	}
+	if article.State == ArticleStateApproved {
+		return article.PublishAt != nil && !article.PublishAt.After(now)
+	}
+	return article.IsPublished()
+}
+
+// IsHidden reports whether the article has been hidden pending moderation,
+// either after reports (HiddenAt) or by content screening holding it for
+// review (HeldAt). The two are settled independently.
+func (article *Article) IsHidden() bool {
+	return article.HiddenAt != nil || article.HeldAt != nil
+}
+
+// IsValidContentFormat reports whether format is a supported content format
+func IsValidContentFormat(format string) bool {
+	switch format {
+	case ContentFormatMarkdown, ContentFormatPlaintext, ContentFormatHTML, ContentFormatBlocks:
+		return true
+	}
+	return false
 }
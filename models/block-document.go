@@ -0,0 +1,114 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// BlockDocumentVersion is the current version of the block document schema
+const BlockDocumentVersion = 1
+
+// Block types supported by the editor
+const (
+	BlockParagraph = "paragraph"
+	BlockHeading   = "heading"
+	BlockImage     = "image"
+	BlockEmbed     = "embed"
+	BlockCode      = "code"
+	BlockQuote     = "quote"
+)
+
+// maxBlocks bounds the size of a single document
+const maxBlocks = 2000
+
+// BlockDocument is a versioned, block-based article body stored as JSONB
+type BlockDocument struct {
+	Version int     `json:"version"`
+	Blocks  []Block `json:"blocks"`
+}
+
+// Block is a single editor block. Which fields are used depends on Type:
+// paragraph and quote use Text (quote may set Caption as its citation),
+// heading uses Text and Level, code uses Text and Language, image uses URL,
+// Alt and Caption, and embed uses URL and Caption.
+type Block struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	Level    int    `json:"level,omitempty"`
+	Language string `json:"language,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Alt      string `json:"alt,omitempty"`
+	Caption  string `json:"caption,omitempty"`
+}
+
+// Validate checks the document against the block schema
+func (doc *BlockDocument) Validate() error {
+	if doc.Version != BlockDocumentVersion {
+		return fmt.Errorf("unsupported block document version %d", doc.Version)
+	}
+	if len(doc.Blocks) == 0 {
+		return errors.New("block document has no blocks")
+	}
+	if len(doc.Blocks) > maxBlocks {
+		return fmt.Errorf("block document has more than %d blocks", maxBlocks)
+	}
+
+	for i, block := range doc.Blocks {
+		if err := block.validate(); err != nil {
+			return fmt.Errorf("block %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (block *Block) validate() error {
+	switch block.Type {
+	case BlockParagraph, BlockQuote, BlockCode:
+		if block.Text == "" {
+			return fmt.Errorf("%s block requires text", block.Type)
+		}
+	case BlockHeading:
+		if block.Text == "" {
+			return errors.New("heading block requires text")
+		}
+		if block.Level < 1 || block.Level > 6 {
+			return errors.New("heading level must be between 1 and 6")
+		}
+	case BlockImage, BlockEmbed:
+		parsed, err := url.Parse(block.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("%s block requires an http(s) url", block.Type)
+		}
+	default:
+		return fmt.Errorf("unknown block type %q", block.Type)
+	}
+	return nil
+}
+
+// Value stores the document as JSON
+func (doc *BlockDocument) Value() (driver.Value, error) {
+	if doc == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan reads the document from a JSON column
+func (doc *BlockDocument) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(data, doc)
+	case string:
+		return json.Unmarshal([]byte(data), doc)
+	}
+	return fmt.Errorf("cannot scan %T into BlockDocument", value)
+}
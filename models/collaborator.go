@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+)
+
+// Collaborator access levels on an article, from least to most privileged.
+// Viewers can read drafts, editors can also change content and move the
+// article through the workflow, and owners can also manage collaborators and
+// delete the article. The article's author is always an owner.
+const (
+	CollaboratorViewer = "viewer"
+	CollaboratorEditor = "editor"
+	CollaboratorOwner  = "owner"
+)
+
+var collaboratorRank = map[string]int{
+	CollaboratorViewer: 1,
+	CollaboratorEditor: 2,
+	CollaboratorOwner:  3,
+}
+
+// IsValidCollaboratorRole reports whether role is a known access level
+func IsValidCollaboratorRole(role string) bool {
+	_, ok := collaboratorRank[role]
+	return ok
+}
+
+// ArticleCollaborator gives a user access to an article they did not create
+type ArticleCollaborator struct {
+	ID          string    `gorm:"primaryKey;<-:create" json:"id"`
+	ArticleID   string    `json:"article_id" gorm:"not null;uniqueIndex:idx_article_collaborator"`
+	UserID      string    `json:"user_id" gorm:"not null;uniqueIndex:idx_article_collaborator;index"`
+	User        User      `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Role        string    `json:"role" gorm:"not null"`
+	InvitedByID string    `json:"invited_by_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// AccessLevel returns the user's access level on the article, or "" if they
+// have none. Collaborators must be preloaded.
+func (article *Article) AccessLevel(userID string) string {
+	if userID == "" {
+		return ""
+	}
+	if article.AuthorID == userID {
+		return CollaboratorOwner
+	}
+	for _, collaborator := range article.Collaborators {
+		if collaborator.UserID == userID {
+			return collaborator.Role
+		}
+	}
+	return ""
+}
+
+// HasAccess reports whether the user holds at least the given access level
+func (article *Article) HasAccess(userID, level string) bool {
+	return collaboratorRank[article.AccessLevel(userID)] >= collaboratorRank[level]
+}
+
+// CoAuthors returns the collaborators credited on the article: editors and
+// owners, but not viewers
+func (article *Article) CoAuthors() []User {
+	var users []User
+	for _, collaborator := range article.Collaborators {
+		if collaborator.Role != CollaboratorViewer {
+			users = append(users, collaborator.User)
+		}
+	}
+	return users
+}
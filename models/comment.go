@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Comment is a reader comment on an article. Replies point at their parent
+// comment; deleted comments keep their row so the thread shape survives.
+type Comment struct {
+	ID         string         `gorm:"primaryKey;<-:create" json:"id"`
+	ArticleID  string         `json:"article_id" gorm:"not null;index"`
+	ParentID   *string        `json:"parent_id,omitempty" gorm:"index"`
+	AuthorID   string         `json:"author_id" gorm:"not null;index"`
+	Author     User           `json:"author,omitempty" gorm:"foreignKey:AuthorID"`
+	Body       string         `json:"body" gorm:"type:text;not null"`
+	EditedAt   *time.Time     `json:"edited_at,omitempty"`
+	ReplyCount int64          `json:"reply_count" gorm:"->;-:migration"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+}
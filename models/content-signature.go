@@ -0,0 +1,53 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// MinHashSignature is a MinHash signature of an article's content, stored
+// as little-endian bytes
+type MinHashSignature []uint32
+
+// Value stores the signature as bytes
+func (signature MinHashSignature) Value() (driver.Value, error) {
+	data := make([]byte, 4*len(signature))
+	for i, value := range signature {
+		binary.LittleEndian.PutUint32(data[4*i:], value)
+	}
+	return data, nil
+}
+
+// Scan reads the signature from a bytea column
+func (signature *MinHashSignature) Scan(value interface{}) error {
+	data, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into MinHashSignature", value)
+	}
+	if len(data)%4 != 0 {
+		return fmt.Errorf("invalid MinHashSignature length %d", len(data))
+	}
+	*signature = make(MinHashSignature, len(data)/4)
+	for i := range *signature {
+		(*signature)[i] = binary.LittleEndian.Uint32(data[4*i:])
+	}
+	return nil
+}
+
+// ArticleSignature holds the MinHash signature of an article's content,
+// used to find near-duplicate articles
+type ArticleSignature struct {
+	ArticleID string           `gorm:"primaryKey" json:"article_id"`
+	Signature MinHashSignature `json:"-" gorm:"type:bytea;not null"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// ArticleSignatureBucket places an article in the LSH bucket of one band of
+// its signature. Articles sharing a bucket are candidate duplicates.
+type ArticleSignatureBucket struct {
+	ArticleID string `gorm:"primaryKey" json:"article_id"`
+	Band      int    `gorm:"primaryKey;autoIncrement:false;index:idx_signature_bucket,priority:1" json:"band"`
+	Bucket    int64  `gorm:"not null;index:idx_signature_bucket,priority:2" json:"bucket"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// ArticleEditLock is a lease on editing an article. There is at most one row
+// per article; a lock whose ExpiresAt has passed no longer counts and can be
+// taken over by anyone. Times are set by the database so that replicas with
+// skewed clocks agree on expiry.
+type ArticleEditLock struct {
+	ArticleID   string    `json:"article_id" gorm:"primaryKey"`
+	HolderID    string    `json:"holder_id" gorm:"not null;index"`
+	Holder      User      `json:"holder,omitempty" gorm:"foreignKey:HolderID"`
+	AcquiredAt  time.Time `json:"acquired_at" gorm:"not null"`
+	HeartbeatAt time.Time `json:"heartbeat_at" gorm:"not null"`
+	ExpiresAt   time.Time `json:"expires_at" gorm:"not null;index"`
+}
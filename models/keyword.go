@@ -0,0 +1,51 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Keywords are the terms that best characterize an article, best first
+type Keywords []string
+
+// Value stores the keywords as JSON
+func (keywords Keywords) Value() (driver.Value, error) {
+	if keywords == nil {
+		keywords = Keywords{}
+	}
+	data, err := json.Marshal(keywords)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan reads the keywords from a JSON column
+func (keywords *Keywords) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(data, keywords)
+	case string:
+		return json.Unmarshal([]byte(data), keywords)
+	}
+	return fmt.Errorf("cannot scan %T into Keywords", value)
+}
+
+// ArticleTerm records that a term occurs in an article. Together they form
+// the corpus keywords are weighed against: the number of articles a term
+// occurs in is its document frequency.
+type ArticleTerm struct {
+	ArticleID string `gorm:"primaryKey" json:"article_id"`
+	Term      string `gorm:"primaryKey;index" json:"term"`
+}
+
+// KeywordCorpus holds the number of articles in the keyword corpus in its
+// single row. It is kept up to date as articles are indexed, so that ranking
+// keywords does not have to count them.
+type KeywordCorpus struct {
+	ID        int   `gorm:"primaryKey" json:"-"`
+	Documents int64 `json:"documents" gorm:"not null;default:0"`
+}
@@ -21,3 +21,78 @@ func (rva *RecentlyViewedArticle) BeforeCreate(tx *gorm.DB) (err error) {
 	rva.ID = "RV" + strings.Replace(uuid.New().String(), "-", "", -1)
 	return
 }
+
+func (transition *ArticleTransition) BeforeCreate(tx *gorm.DB) (err error) {
+	transition.ID = "AT" + strings.Replace(uuid.New().String(), "-", "", -1)
+	return
+}
+
+func (slug *ArticleSlug) BeforeCreate(tx *gorm.DB) (err error) {
+	slug.ID = "AS" + strings.Replace(uuid.New().String(), "-", "", -1)
+	return
+}
+
+func (comment *Comment) BeforeCreate(tx *gorm.DB) (err error) {
+	comment.ID = "CM" + strings.Replace(uuid.New().String(), "-", "", -1)
+	return
+}
+
+func (reaction *ArticleReaction) BeforeCreate(tx *gorm.DB) (err error) {
+	reaction.ID = "RX" + strings.Replace(uuid.New().String(), "-", "", -1)
+	return
+}
+
+func (list *ReadingList) BeforeCreate(tx *gorm.DB) (err error) {
+	list.ID = "RL" + strings.Replace(uuid.New().String(), "-", "", -1)
+	return
+}
+
+func (item *ReadingListItem) BeforeCreate(tx *gorm.DB) (err error) {
+	item.ID = "RI" + strings.Replace(uuid.New().String(), "-", "", -1)
+	return
+}
+
+func (series *Series) BeforeCreate(tx *gorm.DB) (err error) {
+	series.ID = "SR" + strings.Replace(uuid.New().String(), "-", "", -1)
+	return
+}
+
+func (part *SeriesPart) BeforeCreate(tx *gorm.DB) (err error) {
+	part.ID = "SP" + strings.Replace(uuid.New().String(), "-", "", -1)
+	return
+}
+
+func (collaborator *ArticleCollaborator) BeforeCreate(tx *gorm.DB) (err error) {
+	collaborator.ID = "AC" + strings.Replace(uuid.New().String(), "-", "", -1)
+	return
+}
+
+func (suggestion *ArticleSuggestion) BeforeCreate(tx *gorm.DB) (err error) {
+	suggestion.ID = "SG" + strings.Replace(uuid.New().String(), "-", "", -1)
+	return
+}
+
+func (tag *Tag) BeforeCreate(tx *gorm.DB) (err error) {
+	tag.ID = "TG" + strings.Replace(uuid.New().String(), "-", "", -1)
+	return
+}
+
+func (link *ArticlePreviewLink) BeforeCreate(tx *gorm.DB) (err error) {
+	link.ID = "PL" + strings.Replace(uuid.New().String(), "-", "", -1)
+	return
+}
+
+func (report *ContentReport) BeforeCreate(tx *gorm.DB) (err error) {
+	report.ID = "RP" + strings.Replace(uuid.New().String(), "-", "", -1)
+	return
+}
+
+func (action *ModerationAction) BeforeCreate(tx *gorm.DB) (err error) {
+	action.ID = "MA" + strings.Replace(uuid.New().String(), "-", "", -1)
+	return
+}
+
+func (record *ArticleScreening) BeforeCreate(tx *gorm.DB) (err error) {
+	record.ID = "SC" + strings.Replace(uuid.New().String(), "-", "", -1)
+	return
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// ArticlePreviewLink lets someone without an account read an article in any
+// state through a signed token. Links stop working once they expire or are
+// revoked; UseCount records how often they were opened.
+type ArticlePreviewLink struct {
+	ID          string     `gorm:"primaryKey;<-:create" json:"id"`
+	ArticleID   string     `json:"article_id" gorm:"not null;index"`
+	CreatedByID string     `json:"created_by_id" gorm:"not null"`
+	CreatedBy   User       `json:"created_by,omitempty" gorm:"foreignKey:CreatedByID"`
+	Label       string     `json:"label"`
+	ExpiresAt   time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	UseCount    int64      `json:"use_count" gorm:"not null;default:0"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// IsActive reports whether the link can still be opened at the given time
+func (link *ArticlePreviewLink) IsActive(now time.Time) bool {
+	return link.RevokedAt == nil && now.Before(link.ExpiresAt)
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+)
+
+// Reaction types readers can leave on an article
+const (
+	ReactionLike       = "like"
+	ReactionInsightful = "insightful"
+	ReactionCelebrate  = "celebrate"
+	ReactionClap       = "clap"
+)
+
+// IsValidReactionType reports whether reactionType is a known reaction
+func IsValidReactionType(reactionType string) bool {
+	switch reactionType {
+	case ReactionLike, ReactionInsightful, ReactionCelebrate, ReactionClap:
+		return true
+	}
+	return false
+}
+
+// ArticleReaction is one user's reaction of one type on an article
+type ArticleReaction struct {
+	ID        string    `gorm:"primaryKey;<-:create" json:"id"`
+	ArticleID string    `json:"article_id" gorm:"not null;uniqueIndex:idx_article_reaction"`
+	UserID    string    `json:"user_id" gorm:"not null;uniqueIndex:idx_article_reaction;index"`
+	Type      string    `json:"type" gorm:"not null;uniqueIndex:idx_article_reaction"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ArticleReactionCount is the running total of one reaction type on an
+// article, kept in step with ArticleReaction so listings need no COUNT(*)
+type ArticleReactionCount struct {
+	ArticleID string `json:"article_id" gorm:"primaryKey"`
+	Type      string `json:"type" gorm:"primaryKey"`
+	Count     int64  `json:"count" gorm:"not null;default:0"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultReadingListName is the name of the list used by the bookmark shortcut
+const DefaultReadingListName = "Bookmarks"
+
+// ReadingList is a user-curated, ordered collection of articles. Public lists
+// can be shared by link.
+type ReadingList struct {
+	ID          string            `gorm:"primaryKey;<-:create" json:"id"`
+	UserID      string            `json:"user_id" gorm:"not null;index"`
+	User        User              `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Name        string            `json:"name" gorm:"not null"`
+	Description string            `json:"description" gorm:"type:text"`
+	IsPublic    bool              `json:"is_public" gorm:"not null;default:false"`
+	IsDefault   bool              `json:"is_default" gorm:"not null;default:false"`
+	Items       []ReadingListItem `json:"items,omitempty" gorm:"foreignKey:ListID"`
+	ItemCount   int64             `json:"item_count" gorm:"->;-:migration"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt    `json:"deleted_at,omitempty" gorm:"index"`
+}
+
+// ReadingListItem is an article saved in a reading list. Positions are
+// contiguous and start at 1.
+type ReadingListItem struct {
+	ID        string    `gorm:"primaryKey;<-:create" json:"id"`
+	ListID    string    `json:"list_id" gorm:"not null;uniqueIndex:idx_reading_list_article"`
+	ArticleID string    `json:"article_id" gorm:"not null;uniqueIndex:idx_reading_list_article;index"`
+	Article   Article   `json:"article,omitempty" gorm:"foreignKey:ArticleID"`
+	Position  int       `json:"position" gorm:"not null"`
+	Note      string    `json:"note" gorm:"type:text"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
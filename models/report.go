@@ -0,0 +1,128 @@
+package models
+
+import (
+	"time"
+)
+
+// Kinds of content that can be reported
+const (
+	ReportTargetArticle = "article"
+)
+
+// ModerationTargetUser is the target type of moderation actions taken on an
+// account rather than on reported content
+const ModerationTargetUser = "user"
+
+// Reasons a reader can give for reporting content
+const (
+	ReportReasonSpam           = "spam"
+	ReportReasonHarassment     = "harassment"
+	ReportReasonHate           = "hate"
+	ReportReasonMisinformation = "misinformation"
+	ReportReasonCopyright      = "copyright"
+	ReportReasonOther          = "other"
+)
+
+// Report statuses. Open reports become in_review once a moderator is
+// assigned, and end up resolved or dismissed.
+const (
+	ReportOpen      = "open"
+	ReportInReview  = "in_review"
+	ReportResolved  = "resolved"
+	ReportDismissed = "dismissed"
+)
+
+// Actions a moderator can take to resolve a report
+const (
+	ResolutionDismiss   = "dismiss"
+	ResolutionUnpublish = "unpublish"
+	ResolutionDelete    = "delete"
+	ResolutionWarn      = "warn"
+	ResolutionSuspend   = "suspend"
+)
+
+// IsValidReportReason reports whether reason is a known report reason
+func IsValidReportReason(reason string) bool {
+	switch reason {
+	case ReportReasonSpam, ReportReasonHarassment, ReportReasonHate,
+		ReportReasonMisinformation, ReportReasonCopyright, ReportReasonOther:
+		return true
+	}
+	return false
+}
+
+// IsValidReportStatus reports whether status is a known report status
+func IsValidReportStatus(status string) bool {
+	switch status {
+	case ReportOpen, ReportInReview, ReportResolved, ReportDismissed:
+		return true
+	}
+	return false
+}
+
+// IsValidResolution reports whether action is a known resolution action
+func IsValidResolution(action string) bool {
+	switch action {
+	case ResolutionDismiss, ResolutionUnpublish, ResolutionDelete, ResolutionWarn, ResolutionSuspend:
+		return true
+	}
+	return false
+}
+
+// ContentReport is a reader's complaint about a piece of content. TargetID
+// refers to a row of the kind named by TargetType, and TargetUserID to the
+// user responsible for it.
+type ContentReport struct {
+	ID             string     `gorm:"primaryKey;<-:create" json:"id"`
+	TargetType     string     `json:"target_type" gorm:"not null;index:idx_report_target"`
+	TargetID       string     `json:"target_id" gorm:"not null;index:idx_report_target"`
+	TargetUserID   string     `json:"target_user_id" gorm:"not null;index"`
+	ReporterID     string     `json:"reporter_id" gorm:"not null;index"`
+	Reporter       User       `json:"reporter,omitempty" gorm:"foreignKey:ReporterID"`
+	Reason         string     `json:"reason" gorm:"not null"`
+	Details        string     `json:"details" gorm:"type:text"`
+	Status         string     `json:"status" gorm:"not null;default:open;index"`
+	AssigneeID     *string    `json:"assignee_id,omitempty" gorm:"index"`
+	Assignee       *User      `json:"assignee,omitempty" gorm:"foreignKey:AssigneeID"`
+	Resolution     string     `json:"resolution,omitempty"`
+	ResolutionNote string     `json:"resolution_note,omitempty" gorm:"type:text"`
+	ResolvedByID   *string    `json:"resolved_by_id,omitempty"`
+	ResolvedBy     *User      `json:"resolved_by,omitempty" gorm:"foreignKey:ResolvedByID"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// IsOpen reports whether the report still awaits a decision
+func (report *ContentReport) IsOpen() bool {
+	return report.Status == ReportOpen || report.Status == ReportInReview
+}
+
+// Moderation actions recorded in the audit log, on top of the resolution
+// actions
+const (
+	ModerationAssign   = "assign"
+	ModerationUnassign = "unassign"
+	ModerationAutoHide = "auto_hide"
+	// Lifting a suspension, logged against the user rather than a report
+	ModerationLiftSuspension = "lift_suspension"
+	// Decisions on submissions held by content screening
+	ModerationApproveHeld = "approve_held"
+	ModerationRejectHeld  = "reject_held"
+)
+
+// ModerationAction records one thing done by a moderator, or by the system
+// when ActorID is empty. The log is kept when the content it refers to is
+// purged.
+type ModerationAction struct {
+	ID           string    `gorm:"primaryKey;<-:create" json:"id"`
+	ReportID     *string   `json:"report_id,omitempty" gorm:"index"`
+	ActorID      *string   `json:"actor_id,omitempty"`
+	Actor        *User     `json:"actor,omitempty" gorm:"foreignKey:ActorID"`
+	Action       string    `json:"action" gorm:"not null"`
+	TargetType   string    `json:"target_type" gorm:"not null"`
+	TargetID     string    `json:"target_id" gorm:"not null;index"`
+	TargetUserID string    `json:"target_user_id" gorm:"index"`
+	Note         string    `json:"note" gorm:"type:text"`
+	CreatedAt    time.Time `json:"created_at"`
+}
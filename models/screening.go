@@ -0,0 +1,112 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Verdicts of content screening, from least to most severe
+const (
+	ScreeningAllow  = "allow"
+	ScreeningHold   = "hold"
+	ScreeningReject = "reject"
+)
+
+var screeningSeverity = map[string]int{
+	ScreeningAllow:  0,
+	ScreeningHold:   1,
+	ScreeningReject: 2,
+}
+
+// MoreSevereVerdict returns whichever of two verdicts is more severe
+func MoreSevereVerdict(a, b string) string {
+	if screeningSeverity[b] > screeningSeverity[a] {
+		return b
+	}
+	return a
+}
+
+// Review statuses of submissions held by screening
+const (
+	ScreeningReviewPending  = "pending"
+	ScreeningReviewApproved = "approved"
+	ScreeningReviewRejected = "rejected"
+)
+
+// ScreeningReason explains why one check flagged a submission
+type ScreeningReason struct {
+	Check   string  `json:"check"`
+	Verdict string  `json:"verdict"`
+	Message string  `json:"message"`
+	Score   float64 `json:"score,omitempty"`
+}
+
+// ScreeningReasons is the list of reasons stored with a screening
+type ScreeningReasons []ScreeningReason
+
+// Value stores the reasons as JSON
+func (reasons ScreeningReasons) Value() (driver.Value, error) {
+	if reasons == nil {
+		reasons = ScreeningReasons{}
+	}
+	data, err := json.Marshal(reasons)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan reads the reasons from a JSON column
+func (reasons *ScreeningReasons) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(data, reasons)
+	case string:
+		return json.Unmarshal([]byte(data), reasons)
+	}
+	return fmt.Errorf("cannot scan %T into ScreeningReasons", value)
+}
+
+// ArticleScreening records the verdict of content screening on one article
+// submission. Rejected submissions are never saved, so ArticleID is empty
+// for rejected new articles. Held submissions wait for a moderator, who
+// approves or rejects them. Text keeps what was screened, since the article
+// may be edited before the review.
+type ArticleScreening struct {
+	ID           string           `gorm:"primaryKey;<-:create" json:"id"`
+	ArticleID    *string          `json:"article_id,omitempty" gorm:"index"`
+	AuthorID     string           `json:"author_id" gorm:"not null;index"`
+	Author       User             `json:"author,omitempty" gorm:"foreignKey:AuthorID"`
+	Title        string           `json:"title" gorm:"not null"`
+	Text         string           `json:"-" gorm:"type:text"` // the screened text, which the classifier learns from
+	Verdict      string           `json:"verdict" gorm:"not null;index"`
+	Reasons      ScreeningReasons `json:"reasons" gorm:"type:jsonb"`
+	ReviewStatus string           `json:"review_status,omitempty" gorm:"index"`
+	ReviewedByID *string          `json:"reviewed_by_id,omitempty"`
+	ReviewedBy   *User            `json:"reviewed_by,omitempty" gorm:"foreignKey:ReviewedByID"`
+	ReviewedAt   *time.Time       `json:"reviewed_at,omitempty"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
+
+// Classes the spam classifier learns from moderator decisions
+const (
+	SpamClassSpam = "spam"
+	SpamClassHam  = "ham"
+)
+
+// SpamClass counts the documents the spam classifier was trained on per class
+type SpamClass struct {
+	Class     string `gorm:"primaryKey" json:"class"`
+	Documents int64  `json:"documents" gorm:"not null;default:0"`
+}
+
+// SpamToken counts the training documents of each class a token appeared in
+type SpamToken struct {
+	Token     string `gorm:"primaryKey" json:"token"`
+	SpamCount int64  `json:"spam_count" gorm:"not null;default:0"`
+	HamCount  int64  `json:"ham_count" gorm:"not null;default:0"`
+}
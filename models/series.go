@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Series groups an author's articles into an ordered sequence, such as the
+// parts of a long tutorial
+type Series struct {
+	ID          string         `gorm:"primaryKey;<-:create" json:"id"`
+	Title       string         `json:"title" gorm:"not null"`
+	Description string         `json:"description" gorm:"type:text"`
+	AuthorID    string         `json:"author_id" gorm:"not null;index"`
+	Author      User           `json:"author,omitempty" gorm:"foreignKey:AuthorID"`
+	Parts       []SeriesPart   `json:"parts,omitempty" gorm:"foreignKey:SeriesID"`
+	PartCount   int64          `json:"part_count" gorm:"->;-:migration"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+}
+
+// SeriesPart places an article in a series. An article belongs to at most one
+// series, and positions are contiguous starting at 1.
+type SeriesPart struct {
+	ID        string    `gorm:"primaryKey;<-:create" json:"id"`
+	SeriesID  string    `json:"series_id" gorm:"not null;index"`
+	Series    Series    `json:"series,omitempty" gorm:"foreignKey:SeriesID"`
+	ArticleID string    `json:"article_id" gorm:"not null;uniqueIndex"`
+	Article   Article   `json:"article,omitempty" gorm:"foreignKey:ArticleID"`
+	Position  int       `json:"position" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
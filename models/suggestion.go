@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+)
+
+// Suggestion statuses
+const (
+	SuggestionPending   = "pending"
+	SuggestionAccepted  = "accepted"
+	SuggestionRejected  = "rejected"
+	SuggestionWithdrawn = "withdrawn"
+)
+
+// ArticleSuggestion is an edit proposed by someone who cannot edit the
+// article directly. The title and content the suggestion was written against
+// are kept so the author can see a diff and stale suggestions can be detected.
+type ArticleSuggestion struct {
+	ID            string     `gorm:"primaryKey;<-:create" json:"id"`
+	ArticleID     string     `json:"article_id" gorm:"not null;index"`
+	AuthorID      string     `json:"author_id" gorm:"not null;index"`
+	Author        User       `json:"author,omitempty" gorm:"foreignKey:AuthorID"`
+	BaseTitle     string     `json:"base_title" gorm:"not null"`
+	BaseContent   string     `json:"base_content" gorm:"type:text;not null"`
+	Title         string     `json:"title" gorm:"not null"`
+	Content       string     `json:"content" gorm:"type:text;not null"`
+	Comment       string     `json:"comment" gorm:"type:text"`
+	Status        string     `json:"status" gorm:"not null;default:pending;index"`
+	ReviewedByID  *string    `json:"reviewed_by_id,omitempty"`
+	ReviewedBy    *User      `json:"reviewed_by,omitempty" gorm:"foreignKey:ReviewedByID"`
+	ReviewComment string     `json:"review_comment" gorm:"type:text"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// ChangesTitle reports whether the suggestion proposes a new title
+func (suggestion *ArticleSuggestion) ChangesTitle() bool {
+	return suggestion.Title != suggestion.BaseTitle
+}
+
+// ChangesContent reports whether the suggestion proposes new content
+func (suggestion *ArticleSuggestion) ChangesContent() bool {
+	return suggestion.Content != suggestion.BaseContent
+}
+
+// IsStale reports whether the parts of the article the suggestion changes
+// have been edited since it was made
+func (suggestion *ArticleSuggestion) IsStale(article *Article) bool {
+	if suggestion.ChangesTitle() && article.Title != suggestion.BaseTitle {
+		return true
+	}
+	if suggestion.ChangesContent() && article.Content != suggestion.BaseContent {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+// Tag is a topic label shared between articles. Tags are matched by slug so
+// "Go", "go" and "GO" are the same tag; Name keeps the first spelling used.
+type Tag struct {
+	ID        string    `gorm:"primaryKey;<-:create" json:"id"`
+	Name      string    `json:"name" gorm:"not null"`
+	Slug      string    `json:"slug" gorm:"not null;uniqueIndex"`
+	CreatedAt time.Time `json:"created_at"`
+}
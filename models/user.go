@@ -6,13 +6,40 @@ import (
 	"gorm.io/gorm"
 )
 
+// Site-wide user roles
+const (
+	RoleUser      = "user"
+	RoleEditor    = "editor"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
 type User struct {
-		
-	ID        string         `gorm:"primaryKey;<-:create" json:"id"`
-	Name      string         `json:"name" gorm:"not null"`
-	Email     string         `json:"email" gorm:"uniqueIndex;not null"`
-	Password  string         `json:"password" gorm:"not null"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+	ID             string         `gorm:"primaryKey;<-:create" json:"id"`
+	Name           string         `json:"name" gorm:"not null"`
+	Email          string         `json:"email" gorm:"uniqueIndex;not null"`
+	Password       string         `json:"password" gorm:"not null"`
+	Role           string         `json:"role" gorm:"not null;default:user"`
+	Version        int64          `json:"version" gorm:"not null;default:1"`
+	SuspendedAt    *time.Time     `json:"suspended_at,omitempty"`
+	SuspendedUntil *time.Time     `json:"suspended_until,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+}
+
+// IsEditor reports whether the user can act as an editor on any article
+func (user *User) IsEditor() bool {
+	return user.Role == RoleEditor || user.Role == RoleAdmin
+}
+
+// IsModerator reports whether the user can work the moderation queue
+func (user *User) IsModerator() bool {
+	return user.Role == RoleModerator || user.Role == RoleAdmin
+}
+
+// IsSuspended reports whether the account is suspended at the given time.
+// Suspensions without a SuspendedUntil last until they are lifted.
+func (user *User) IsSuspended(now time.Time) bool {
+	return user.SuspendedAt != nil && (user.SuspendedUntil == nil || now.Before(*user.SuspendedUntil))
 }
@@ -0,0 +1,46 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Job is a unit of background work that runs on every scheduler tick.
+// Jobs are expected to guard themselves with an advisory lock when they
+// must not run concurrently across replicas.
+type Job struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Start runs all jobs immediately and then once per interval until ctx is
+// cancelled. Running on startup lets the server catch up after downtime.
+// A non-positive interval disables the scheduler.
+func Start(ctx context.Context, interval time.Duration, jobs ...Job) {
+	if interval <= 0 {
+		log.Println("Scheduler: disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		runJobs(ctx, jobs)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func runJobs(ctx context.Context, jobs []Job) {
+	for _, job := range jobs {
+		if err := job.Run(ctx); err != nil {
+			log.Printf("Scheduler: job %s failed: %v", job.Name, err)
+		}
+	}
+}
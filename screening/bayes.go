@@ -0,0 +1,127 @@
+package screening
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"Praiseson6065/ocrolus-be/models"
+)
+
+// Tokenizer limits
+const (
+	minTokenLength = 3
+	maxTokenLength = 24
+	maxTokens      = 500
+)
+
+// minTrainingDocuments is the number of documents of each class the
+// classifier must have seen before its verdicts are trusted
+const minTrainingDocuments = 10
+
+// TokenCount is the number of spam and ham training documents a token
+// appeared in
+type TokenCount struct {
+	Spam int64
+	Ham  int64
+}
+
+// SpamModel gives the classifier access to its training data
+type SpamModel interface {
+	// Documents returns the number of spam and ham training documents
+	Documents(ctx context.Context) (spam, ham int64, err error)
+	// TokenCounts returns the counts of the given tokens; unknown tokens
+	// may be left out
+	TokenCounts(ctx context.Context, tokens []string) (map[string]TokenCount, error)
+}
+
+// Tokens returns the distinct tokens of a document as used by the
+// classifier, in a stable order. Very short and very long words carry little
+// signal and are dropped, and long documents are cut off.
+func Tokens(content *Content) []string {
+	seen := map[string]bool{}
+	var tokens []string
+	for _, word := range Words(content.Title + " " + content.Text) {
+		length := len([]rune(word))
+		if length < minTokenLength || length > maxTokenLength || seen[word] {
+			continue
+		}
+		seen[word] = true
+		tokens = append(tokens, word)
+		if len(tokens) == maxTokens {
+			break
+		}
+	}
+	sort.Strings(tokens)
+	return tokens
+}
+
+// NaiveBayes estimates the probability that content is spam with a naive
+// Bayes classifier over the presence of tokens, trained from moderator
+// decisions. Content at or above holdPercent is held for review, and at or
+// above rejectPercent rejected.
+type NaiveBayes struct {
+	model         SpamModel
+	holdPercent   int
+	rejectPercent int
+}
+
+// NewNaiveBayes returns a classifier check. A threshold of 0 disables the
+// corresponding verdict.
+func NewNaiveBayes(model SpamModel, holdPercent, rejectPercent int) *NaiveBayes {
+	return &NaiveBayes{model: model, holdPercent: holdPercent, rejectPercent: rejectPercent}
+}
+
+func (n *NaiveBayes) Name() string {
+	return "spam_classifier"
+}
+
+func (n *NaiveBayes) Screen(ctx context.Context, content *Content) (*models.ScreeningReason, error) {
+	if n.holdPercent <= 0 && n.rejectPercent <= 0 {
+		return nil, nil
+	}
+
+	spamDocs, hamDocs, err := n.model.Documents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if spamDocs < minTrainingDocuments || hamDocs < minTrainingDocuments {
+		return nil, nil
+	}
+
+	tokens := Tokens(content)
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	counts, err := n.model.TokenCounts(ctx, tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	// Work with log odds to avoid underflow; Laplace smoothing keeps tokens
+	// seen in only one class from deciding on their own
+	logOdds := math.Log(float64(spamDocs)) - math.Log(float64(hamDocs))
+	for _, token := range tokens {
+		count := counts[token]
+		pSpam := float64(count.Spam+1) / float64(spamDocs+2)
+		pHam := float64(count.Ham+1) / float64(hamDocs+2)
+		logOdds += math.Log(pSpam) - math.Log(pHam)
+	}
+	percent := 100 / (1 + math.Exp(-logOdds))
+
+	verdict := ""
+	switch {
+	case n.rejectPercent > 0 && percent >= float64(n.rejectPercent):
+		verdict = models.ScreeningReject
+	case n.holdPercent > 0 && percent >= float64(n.holdPercent):
+		verdict = models.ScreeningHold
+	default:
+		return nil, nil
+	}
+	return &models.ScreeningReason{
+		Verdict: verdict,
+		Message: fmt.Sprintf("looks like spam (%.1f%%)", percent),
+		Score:   percent,
+	}, nil
+}
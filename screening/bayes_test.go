@@ -0,0 +1,75 @@
+package screening
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"Praiseson6065/ocrolus-be/models"
+)
+
+// fakeSpamModel serves fixed training data
+type fakeSpamModel struct {
+	spam, ham int64
+	counts    map[string]TokenCount
+	err       error
+}
+
+func (m *fakeSpamModel) Documents(ctx context.Context) (int64, int64, error) {
+	return m.spam, m.ham, m.err
+}
+
+func (m *fakeSpamModel) TokenCounts(ctx context.Context, tokens []string) (map[string]TokenCount, error) {
+	return m.counts, m.err
+}
+
+func TestTokens(t *testing.T) {
+	got := Tokens(&Content{Title: "Cheap Pills", Text: "cheap pills, an offer! x 42 supercalifragilisticexpialidocious"})
+	want := []string{"cheap", "offer", "pills"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokens = %v, want %v", got, want)
+	}
+}
+
+func TestNaiveBayes(t *testing.T) {
+	spammy := map[string]TokenCount{"casino": {Spam: 90}, "jackpot": {Spam: 90}, "winner": {Spam: 90}}
+	hammy := map[string]TokenCount{"casino": {Ham: 90}, "jackpot": {Ham: 90}, "winner": {Ham: 90}}
+	content := &Content{Title: "Casino jackpot", Text: "winner"}
+
+	tests := []struct {
+		name         string
+		model        *fakeSpamModel
+		hold, reject int
+		content      *Content
+		want         string // empty for no verdict
+	}{
+		{"spam over both thresholds", &fakeSpamModel{spam: 100, ham: 100, counts: spammy}, 90, 99, content, models.ScreeningReject},
+		{"spam without rejection", &fakeSpamModel{spam: 100, ham: 100, counts: spammy}, 90, 0, content, models.ScreeningHold},
+		{"spam without hold", &fakeSpamModel{spam: 100, ham: 100, counts: spammy}, 0, 99, content, models.ScreeningReject},
+		{"ham", &fakeSpamModel{spam: 100, ham: 100, counts: hammy}, 90, 99, content, ""},
+		// Unknown tokens leave the prior of 50%, and thresholds are inclusive
+		{"at the hold threshold", &fakeSpamModel{spam: 100, ham: 100}, 50, 99, content, models.ScreeningHold},
+		{"below the hold threshold", &fakeSpamModel{spam: 100, ham: 100}, 51, 99, content, ""},
+		{"too little training", &fakeSpamModel{spam: minTrainingDocuments - 1, ham: 100, counts: spammy}, 90, 99, content, ""},
+		{"no tokens", &fakeSpamModel{spam: 100, ham: 100, counts: spammy}, 1, 99, &Content{Text: "a b c"}, ""},
+		{"disabled", &fakeSpamModel{err: errors.New("not called")}, 0, 0, content, ""},
+	}
+	for _, tt := range tests {
+		reason, err := NewNaiveBayes(tt.model, tt.hold, tt.reject).Screen(context.Background(), tt.content)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if got := verdictOf(reason); got != tt.want {
+			t.Errorf("%s: verdict %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNaiveBayesModelError(t *testing.T) {
+	check := NewNaiveBayes(&fakeSpamModel{err: errors.New("unavailable")}, 90, 99)
+	if _, err := check.Screen(context.Background(), &Content{Text: "casino jackpot"}); err == nil {
+		t.Error("expected the model error to be returned")
+	}
+}
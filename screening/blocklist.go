@@ -0,0 +1,91 @@
+package screening
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"Praiseson6065/ocrolus-be/models"
+)
+
+// DefaultBlocklist holds phrases that are common in spam submissions
+var DefaultBlocklist = []string{
+	"buy now", "casino", "cheap pills", "crypto giveaway", "free money",
+	"guaranteed income", "payday loan", "viagra", "work from home and earn",
+}
+
+// Blocklist flags content containing terms from a dictionary. Terms match
+// whole words case-insensitively and may span several words. Content
+// matching at least rejectAt distinct terms is rejected, anything less is
+// held for review.
+type Blocklist struct {
+	terms    []string
+	rejectAt int
+}
+
+// NewBlocklist builds a blocklist from the given terms. A rejectAt of 0
+// never rejects.
+func NewBlocklist(terms []string, rejectAt int) *Blocklist {
+	blocklist := &Blocklist{rejectAt: rejectAt}
+	seen := map[string]bool{}
+	for _, term := range terms {
+		term = strings.Join(Words(term), " ")
+		if term != "" && !seen[term] {
+			seen[term] = true
+			blocklist.terms = append(blocklist.terms, term)
+		}
+	}
+	return blocklist
+}
+
+// LoadBlocklistFile reads terms from a file with one term per line. Blank
+// lines and lines starting with # are ignored.
+func LoadBlocklistFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var terms []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		terms = append(terms, line)
+	}
+	return terms, scanner.Err()
+}
+
+func (b *Blocklist) Name() string {
+	return "blocklist"
+}
+
+func (b *Blocklist) Screen(ctx context.Context, content *Content) (*models.ScreeningReason, error) {
+	// Padding with spaces lets terms match whole words only
+	text := " " + strings.Join(Words(content.Title+" "+content.Text), " ") + " "
+
+	var matched []string
+	for _, term := range b.terms {
+		if strings.Contains(text, " "+term+" ") {
+			matched = append(matched, term)
+		}
+	}
+	if len(matched) == 0 {
+		return nil, nil
+	}
+
+	verdict := models.ScreeningHold
+	if b.rejectAt > 0 && len(matched) >= b.rejectAt {
+		verdict = models.ScreeningReject
+	}
+	return &models.ScreeningReason{
+		Verdict: verdict,
+		Message: fmt.Sprintf("contains blocked terms: %s", strings.Join(matched, ", ")),
+		Score:   float64(len(matched)),
+	}, nil
+}
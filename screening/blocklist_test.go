@@ -0,0 +1,42 @@
+package screening
+
+import (
+	"context"
+	"testing"
+
+	"Praiseson6065/ocrolus-be/models"
+)
+
+func TestBlocklist(t *testing.T) {
+	terms := []string{"casino", "Free Money", "pills", "", "CASINO"}
+
+	tests := []struct {
+		name     string
+		rejectAt int
+		content  *Content
+		want     string
+		score    float64
+	}{
+		{"no match", 2, &Content{Text: "A quiet article about gardening"}, "", 0},
+		{"match in title", 2, &Content{Title: "Casino night", Text: "Fun"}, models.ScreeningHold, 1},
+		{"whole words only", 2, &Content{Text: "Casinos and spills"}, "", 0},
+		{"multi-word term", 2, &Content{Text: "Get free, money!"}, models.ScreeningHold, 1},
+		{"split multi-word term", 2, &Content{Text: "free of money"}, "", 0},
+		{"duplicate terms count once", 2, &Content{Text: "casino casino"}, models.ScreeningHold, 1},
+		{"reject at count", 2, &Content{Text: "casino pills"}, models.ScreeningReject, 2},
+		{"never reject", 0, &Content{Text: "casino pills free money"}, models.ScreeningHold, 3},
+	}
+	for _, tt := range tests {
+		reason, err := NewBlocklist(terms, tt.rejectAt).Screen(context.Background(), tt.content)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if got := verdictOf(reason); got != tt.want {
+			t.Errorf("%s: verdict %q, want %q", tt.name, got, tt.want)
+		}
+		if reason != nil && reason.Score != tt.score {
+			t.Errorf("%s: score %v, want %v", tt.name, reason.Score, tt.score)
+		}
+	}
+}
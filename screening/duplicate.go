@@ -0,0 +1,54 @@
+package screening
+
+import (
+	"context"
+	"fmt"
+
+	"Praiseson6065/ocrolus-be/models"
+)
+
+// SimilarityIndex finds stored articles resembling a submission
+type SimilarityIndex interface {
+	// MostSimilar returns the article by another author that content most
+	// resembles, with its similarity between 0 and 1, or an empty ID when
+	// there is none
+	MostSimilar(ctx context.Context, content *Content) (articleID string, similarity float64, err error)
+}
+
+// Duplicate flags content that is largely copied from another author's
+// article. Content sharing more than maxPercent of its text with one is held
+// for review.
+type Duplicate struct {
+	index      SimilarityIndex
+	maxPercent int
+}
+
+// NewDuplicate returns a near-duplicate check. A limit of 0 disables it.
+func NewDuplicate(index SimilarityIndex, maxPercent int) *Duplicate {
+	return &Duplicate{index: index, maxPercent: maxPercent}
+}
+
+func (d *Duplicate) Name() string {
+	return "duplicate"
+}
+
+func (d *Duplicate) Screen(ctx context.Context, content *Content) (*models.ScreeningReason, error) {
+	if d.maxPercent <= 0 {
+		return nil, nil
+	}
+
+	articleID, similarity, err := d.index.MostSimilar(ctx, content)
+	if err != nil || articleID == "" {
+		return nil, err
+	}
+
+	percent := similarity * 100
+	if percent <= float64(d.maxPercent) {
+		return nil, nil
+	}
+	return &models.ScreeningReason{
+		Verdict: models.ScreeningHold,
+		Message: fmt.Sprintf("%.0f%% similar to article %s", percent, articleID),
+		Score:   percent,
+	}, nil
+}
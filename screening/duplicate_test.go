@@ -0,0 +1,50 @@
+package screening
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"Praiseson6065/ocrolus-be/models"
+)
+
+// fakeIndex reports a fixed most similar article
+type fakeIndex struct {
+	articleID  string
+	similarity float64
+	err        error
+}
+
+func (i *fakeIndex) MostSimilar(ctx context.Context, content *Content) (string, float64, error) {
+	return i.articleID, i.similarity, i.err
+}
+
+func TestDuplicate(t *testing.T) {
+	tests := []struct {
+		name  string
+		index *fakeIndex
+		max   int
+		want  string
+	}{
+		{"no similar article", &fakeIndex{}, 80, ""},
+		{"below the limit", &fakeIndex{articleID: "a1", similarity: 0.5}, 80, ""},
+		{"at the limit", &fakeIndex{articleID: "a1", similarity: 0.8}, 80, ""},
+		{"over the limit", &fakeIndex{articleID: "a1", similarity: 0.95}, 80, models.ScreeningHold},
+		{"disabled", &fakeIndex{articleID: "a1", similarity: 1}, 0, ""},
+	}
+	for _, tt := range tests {
+		reason, err := NewDuplicate(tt.index, tt.max).Screen(context.Background(), &Content{})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if got := verdictOf(reason); got != tt.want {
+			t.Errorf("%s: verdict %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	check := NewDuplicate(&fakeIndex{err: errors.New("unavailable")}, 80)
+	if _, err := check.Screen(context.Background(), &Content{}); err == nil {
+		t.Error("expected the index error to be returned")
+	}
+}
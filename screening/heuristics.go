@@ -0,0 +1,114 @@
+package screening
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"Praiseson6065/ocrolus-be/models"
+)
+
+// minHeuristicWords is the shortest text the link density and repetition
+// heuristics judge; shorter texts give too little signal
+const minHeuristicWords = 20
+
+// bareURLPattern matches URLs written out in text
+var bareURLPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+// Words splits text into lowercase words of letters and digits
+func Words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// LinkDensity flags content with many links for its length. More than
+// maxPer100Words links per hundred words holds the content for review, and
+// more than twice as many rejects it.
+type LinkDensity struct {
+	maxPer100Words int
+}
+
+// NewLinkDensity returns a link density check. A limit of 0 disables it.
+func NewLinkDensity(maxPer100Words int) *LinkDensity {
+	return &LinkDensity{maxPer100Words: maxPer100Words}
+}
+
+func (l *LinkDensity) Name() string {
+	return "link_density"
+}
+
+func (l *LinkDensity) Screen(ctx context.Context, content *Content) (*models.ScreeningReason, error) {
+	words := len(strings.Fields(content.Text))
+	if l.maxPer100Words <= 0 || words < minHeuristicWords {
+		return nil, nil
+	}
+
+	// Linked URLs usually appear in the text as well, so take the larger
+	// of the two counts rather than adding them up
+	links := strings.Count(content.HTML, "<a ")
+	if bare := len(bareURLPattern.FindAllString(content.Text, -1)); bare > links {
+		links = bare
+	}
+
+	density := float64(links) * 100 / float64(words)
+	if density <= float64(l.maxPer100Words) {
+		return nil, nil
+	}
+
+	verdict := models.ScreeningHold
+	if density > float64(2*l.maxPer100Words) {
+		verdict = models.ScreeningReject
+	}
+	return &models.ScreeningReason{
+		Verdict: verdict,
+		Message: fmt.Sprintf("%d links in %d words", links, words),
+		Score:   density,
+	}, nil
+}
+
+// Repetition flags content that keeps repeating the same phrases, measured
+// as the share of three-word phrases that occurred earlier in the text.
+// Content above maxPercent is held for review.
+type Repetition struct {
+	maxPercent int
+}
+
+// NewRepetition returns a repetition check. A limit of 0 disables it.
+func NewRepetition(maxPercent int) *Repetition {
+	return &Repetition{maxPercent: maxPercent}
+}
+
+func (r *Repetition) Name() string {
+	return "repetition"
+}
+
+func (r *Repetition) Screen(ctx context.Context, content *Content) (*models.ScreeningReason, error) {
+	words := Words(content.Text)
+	if r.maxPercent <= 0 || len(words) < minHeuristicWords {
+		return nil, nil
+	}
+
+	seen := map[string]bool{}
+	repeated := 0
+	shingles := len(words) - 2
+	for i := 0; i < shingles; i++ {
+		shingle := words[i] + " " + words[i+1] + " " + words[i+2]
+		if seen[shingle] {
+			repeated++
+		}
+		seen[shingle] = true
+	}
+
+	percent := float64(repeated) * 100 / float64(shingles)
+	if percent <= float64(r.maxPercent) {
+		return nil, nil
+	}
+	return &models.ScreeningReason{
+		Verdict: models.ScreeningHold,
+		Message: fmt.Sprintf("%.0f%% of the text repeats earlier phrases", percent),
+		Score:   percent,
+	}, nil
+}
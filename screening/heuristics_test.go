@@ -0,0 +1,75 @@
+package screening
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"Praiseson6065/ocrolus-be/models"
+)
+
+func TestWords(t *testing.T) {
+	got := Words("Hello, World! It's 2024—naïve café.")
+	want := []string{"hello", "world", "it", "s", "2024", "naïve", "café"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Words = %q, want %q", got, want)
+	}
+}
+
+func TestLinkDensity(t *testing.T) {
+	// 100 words, of which links make up the given number
+	text := func(links int) string {
+		return strings.Repeat("https://spam.example ", links) + distinctWords(100-links)
+	}
+
+	tests := []struct {
+		name    string
+		max     int
+		content *Content
+		want    string
+	}{
+		{"under the limit", 5, &Content{Text: text(5)}, ""},
+		{"over the limit", 5, &Content{Text: text(6)}, models.ScreeningHold},
+		{"at twice the limit", 5, &Content{Text: text(10)}, models.ScreeningHold},
+		{"over twice the limit", 5, &Content{Text: text(11)}, models.ScreeningReject},
+		{"anchors count", 5, &Content{Text: distinctWords(100), HTML: strings.Repeat(`<a href="#">x</a>`, 6)}, models.ScreeningHold},
+		{"too short", 1, &Content{Text: "https://a.example https://b.example " + distinctWords(minHeuristicWords-3)}, ""},
+		{"disabled", 0, &Content{Text: text(50)}, ""},
+	}
+	for _, tt := range tests {
+		reason, err := NewLinkDensity(tt.max).Screen(context.Background(), tt.content)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if got := verdictOf(reason); got != tt.want {
+			t.Errorf("%s: verdict %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRepetition(t *testing.T) {
+	tests := []struct {
+		name string
+		max  int
+		text string
+		want string
+	}{
+		{"no repetition", 10, distinctWords(50), ""},
+		{"repeated phrase", 10, strings.Repeat("buy cheap pills now ", 10), models.ScreeningHold},
+		{"some repetition", 50, distinctWords(40) + " " + distinctWords(10), ""},
+		{"too short", 10, strings.Repeat("buy now ", (minHeuristicWords-1)/2), ""},
+		{"disabled", 0, strings.Repeat("buy cheap pills now ", 10), ""},
+	}
+	for _, tt := range tests {
+		reason, err := NewRepetition(tt.max).Screen(context.Background(), &Content{Text: tt.text})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if got := verdictOf(reason); got != tt.want {
+			t.Errorf("%s: verdict %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package screening
+
+import (
+	"context"
+	"log"
+
+	"Praiseson6065/ocrolus-be/models"
+)
+
+// Content is a submission as seen by the checks. Text is the plain text of
+// the rendered body and HTML its sanitized rendering. ArticleID is empty for
+// new articles.
+type Content struct {
+	ArticleID string
+	AuthorID  string
+	Title     string
+	Text      string
+	HTML      string
+}
+
+// Check is one step of the screening pipeline. It returns a reason when it
+// flags the content and nil otherwise. Checks must be safe for concurrent use.
+type Check interface {
+	Name() string
+	Screen(ctx context.Context, content *Content) (*models.ScreeningReason, error)
+}
+
+// Result is the combined verdict of all checks, with the reasons of those
+// that flagged the content
+type Result struct {
+	Verdict string
+	Reasons models.ScreeningReasons
+}
+
+// Pipeline runs submissions through a list of checks
+type Pipeline struct {
+	checks []Check
+}
+
+// NewPipeline returns a pipeline running the given checks in order
+func NewPipeline(checks ...Check) *Pipeline {
+	return &Pipeline{checks: checks}
+}
+
+// Add appends a check to the pipeline. It must not be called while the
+// pipeline is in use.
+func (p *Pipeline) Add(check Check) {
+	p.checks = append(p.checks, check)
+}
+
+// Run screens content with every check. The verdict is the most severe one
+// returned by any check. A failing check is logged and skipped so that an
+// unavailable classifier does not block authors.
+func (p *Pipeline) Run(ctx context.Context, content *Content) Result {
+	result := Result{Verdict: models.ScreeningAllow, Reasons: models.ScreeningReasons{}}
+	for _, check := range p.checks {
+		reason, err := check.Screen(ctx, content)
+		if err != nil {
+			log.Printf("Screening: check %s failed: %v", check.Name(), err)
+			continue
+		}
+		if reason == nil {
+			continue
+		}
+		reason.Check = check.Name()
+		result.Reasons = append(result.Reasons, *reason)
+		result.Verdict = models.MoreSevereVerdict(result.Verdict, reason.Verdict)
+	}
+	return result
+}
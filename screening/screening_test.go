@@ -0,0 +1,80 @@
+package screening
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"Praiseson6065/ocrolus-be/models"
+)
+
+// verdictOf returns the verdict of a reason, or an empty string for none
+func verdictOf(reason *models.ScreeningReason) string {
+	if reason == nil {
+		return ""
+	}
+	return reason.Verdict
+}
+
+// distinctWords returns text of n words that never repeat
+func distinctWords(n int) string {
+	words := make([]string, n)
+	for i := range words {
+		words[i] = fmt.Sprintf("word%d", i)
+	}
+	return strings.Join(words, " ")
+}
+
+// fixedCheck returns the same reason or error for all content
+type fixedCheck struct {
+	name   string
+	reason *models.ScreeningReason
+	err    error
+}
+
+func (c *fixedCheck) Name() string {
+	return c.name
+}
+
+func (c *fixedCheck) Screen(ctx context.Context, content *Content) (*models.ScreeningReason, error) {
+	if c.reason == nil {
+		return nil, c.err
+	}
+	reason := *c.reason
+	return &reason, c.err
+}
+
+func TestPipeline(t *testing.T) {
+	hold := &fixedCheck{name: "hold", reason: &models.ScreeningReason{Verdict: models.ScreeningHold}}
+	reject := &fixedCheck{name: "reject", reason: &models.ScreeningReason{Verdict: models.ScreeningReject}}
+	pass := &fixedCheck{name: "pass"}
+	failing := &fixedCheck{name: "failing", reason: &models.ScreeningReason{Verdict: models.ScreeningReject}, err: errors.New("unavailable")}
+
+	tests := []struct {
+		name    string
+		checks  []Check
+		verdict string
+		reasons []string
+	}{
+		{"no checks", nil, models.ScreeningAllow, nil},
+		{"nothing flagged", []Check{pass}, models.ScreeningAllow, nil},
+		{"hold", []Check{pass, hold}, models.ScreeningHold, []string{"hold"}},
+		{"most severe wins", []Check{reject, hold}, models.ScreeningReject, []string{"reject", "hold"}},
+		{"failing check skipped", []Check{failing, hold}, models.ScreeningHold, []string{"hold"}},
+	}
+	for _, tt := range tests {
+		result := NewPipeline(tt.checks...).Run(context.Background(), &Content{})
+		if result.Verdict != tt.verdict {
+			t.Errorf("%s: verdict %q, want %q", tt.name, result.Verdict, tt.verdict)
+		}
+		var names []string
+		for _, reason := range result.Reasons {
+			names = append(names, reason.Check)
+		}
+		if strings.Join(names, ",") != strings.Join(tt.reasons, ",") {
+			t.Errorf("%s: reasons from %v, want %v", tt.name, names, tt.reasons)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package util
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"Praiseson6065/ocrolus-be/models"
+)
+
+// RenderBlocksHTML renders a block document to HTML. Block text may contain
+// inline Markdown such as emphasis and links. The result should be passed
+// through SanitizeHTML before it is served.
+func RenderBlocksHTML(doc *models.BlockDocument) string {
+	r := &markdownRenderer{anchors: map[string]int{}}
+
+	for _, block := range doc.Blocks {
+		switch block.Type {
+		case models.BlockParagraph:
+			r.builder.WriteString("<p>" + r.renderInline(block.Text) + "</p>\n")
+
+		case models.BlockHeading:
+			r.renderHeading(block.Level, block.Text)
+
+		case models.BlockQuote:
+			r.builder.WriteString("<blockquote>\n<p>" + r.renderInline(block.Text) + "</p>\n")
+			if block.Caption != "" {
+				r.builder.WriteString("<p>— " + r.renderInline(block.Caption) + "</p>\n")
+			}
+			r.builder.WriteString("</blockquote>\n")
+
+		case models.BlockCode:
+			r.builder.WriteString("<pre><code")
+			if block.Language != "" {
+				r.builder.WriteString(` class="language-` + html.EscapeString(strings.ToLower(block.Language)) + `"`)
+			}
+			r.builder.WriteString(">" + html.EscapeString(block.Text) + "\n</code></pre>\n")
+
+		case models.BlockImage:
+			r.builder.WriteString(`<figure><img src="` + html.EscapeString(block.URL) + `" alt="` + html.EscapeString(block.Alt) + `">`)
+			if block.Caption != "" {
+				r.builder.WriteString("<figcaption>" + r.renderInline(block.Caption) + "</figcaption>")
+			}
+			r.builder.WriteString("</figure>\n")
+
+		case models.BlockEmbed:
+			// Embeds are linked rather than framed so no third-party markup is served
+			label := block.Caption
+			if label == "" {
+				label = block.URL
+			}
+			r.builder.WriteString(`<figure><a href="` + html.EscapeString(block.URL) + `">` + html.EscapeString(label) + "</a></figure>\n")
+		}
+	}
+
+	return r.builder.String()
+}
+
+// RenderBlocksMarkdown renders a block document to Markdown
+func RenderBlocksMarkdown(doc *models.BlockDocument) string {
+	parts := make([]string, 0, len(doc.Blocks))
+
+	for _, block := range doc.Blocks {
+		switch block.Type {
+		case models.BlockParagraph:
+			parts = append(parts, block.Text)
+
+		case models.BlockHeading:
+			parts = append(parts, strings.Repeat("#", block.Level)+" "+block.Text)
+
+		case models.BlockQuote:
+			quote := "> " + strings.ReplaceAll(block.Text, "\n", "\n> ")
+			if block.Caption != "" {
+				quote += "\n>\n> — " + block.Caption
+			}
+			parts = append(parts, quote)
+
+		case models.BlockCode:
+			// Use a fence longer than any backtick run inside the code
+			fence := "```"
+			for strings.Contains(block.Text, fence) {
+				fence += "`"
+			}
+			parts = append(parts, fence+block.Language+"\n"+block.Text+"\n"+fence)
+
+		case models.BlockImage:
+			image := fmt.Sprintf("![%s](%s)", block.Alt, block.URL)
+			if block.Caption != "" {
+				image += "\n*" + block.Caption + "*"
+			}
+			parts = append(parts, image)
+
+		case models.BlockEmbed:
+			label := block.Caption
+			if label == "" {
+				label = block.URL
+			}
+			parts = append(parts, fmt.Sprintf("[%s](%s)", label, block.URL))
+		}
+	}
+
+	return strings.Join(parts, "\n\n") + "\n"
+}
+
+// BlocksPlaintext extracts the readable text of a block document, one block
+// per paragraph, for search and previews
+func BlocksPlaintext(doc *models.BlockDocument) string {
+	r := &markdownRenderer{anchors: map[string]int{}}
+	inlineText := func(text string) string {
+		return HTMLToText(r.renderInline(text))
+	}
+	parts := make([]string, 0, len(doc.Blocks))
+
+	for _, block := range doc.Blocks {
+		var text string
+		switch block.Type {
+		case models.BlockParagraph, models.BlockHeading:
+			text = inlineText(block.Text)
+		case models.BlockCode:
+			text = block.Text
+		case models.BlockQuote:
+			text = inlineText(block.Text)
+			if block.Caption != "" {
+				text += "\n— " + inlineText(block.Caption)
+			}
+		case models.BlockImage:
+			text = block.Alt + " " + inlineText(block.Caption)
+		case models.BlockEmbed:
+			text = inlineText(block.Caption)
+		}
+		if text = strings.TrimSpace(text); text != "" {
+			parts = append(parts, text)
+		}
+	}
+
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,172 @@
+package util
+
+import (
+	"strings"
+)
+
+// Line diff operations
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// maxDiffEdits bounds the work done by DiffLines. Texts that differ by more
+// lines than this are reported as a wholesale replacement of the changed
+// region.
+const maxDiffEdits = 1000
+
+// DiffLine is one line of a line-based diff
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// DiffLines returns the line-by-line differences between two texts as a
+// minimal sequence of equal, deleted and inserted lines (Myers' algorithm)
+func DiffLines(before, after string) []DiffLine {
+	a := splitLines(before)
+	b := splitLines(after)
+
+	// Common leading and trailing lines never need to go through the search
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var diff []DiffLine
+	for _, line := range a[:prefix] {
+		diff = append(diff, DiffLine{Op: DiffEqual, Text: line})
+	}
+	diff = append(diff, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		diff = append(diff, DiffLine{Op: DiffEqual, Text: line})
+	}
+	return diff
+}
+
+func splitLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffMiddle runs the Myers search, keeping the furthest-reaching x for each
+// diagonal at every edit distance so the path can be traced back
+func diffMiddle(a, b []string) []DiffLine {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+
+	// trace[d][k+d] is the furthest x reached on diagonal k after d edits
+	var trace [][]int
+	previous := []int{0, 0}
+	found := false
+	for d := 0; d <= n+m && d <= maxDiffEdits; d++ {
+		current := make([]int, 2*d+1)
+		get := func(k int) int {
+			// Diagonals outside the previous round's range are unreachable
+			index := k + d - 1
+			if index < 0 || index >= len(previous) {
+				return -1
+			}
+			return previous[index]
+		}
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if d == 0 {
+				x = 0
+			} else if k == -d || (k != d && get(k-1) < get(k+1)) {
+				x = get(k + 1)
+			} else {
+				x = get(k-1) + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			current[k+d] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+		trace = append(trace, current)
+		previous = current
+		if found {
+			break
+		}
+	}
+
+	if !found {
+		return replaceLines(a, b)
+	}
+	return backtrackDiff(a, b, trace)
+}
+
+func backtrackDiff(a, b []string, trace [][]int) []DiffLine {
+	var reversed []DiffLine
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d > 0; d-- {
+		previous := trace[d-1]
+		get := func(k int) int {
+			index := k + d - 1
+			if index < 0 || index >= len(previous) {
+				return -1
+			}
+			return previous[index]
+		}
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && get(k-1) < get(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := get(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, DiffLine{Op: DiffEqual, Text: a[x-1]})
+			x--
+			y--
+		}
+		if x == prevX {
+			reversed = append(reversed, DiffLine{Op: DiffInsert, Text: b[y-1]})
+		} else {
+			reversed = append(reversed, DiffLine{Op: DiffDelete, Text: a[x-1]})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		reversed = append(reversed, DiffLine{Op: DiffEqual, Text: a[x-1]})
+		x--
+		y--
+	}
+
+	diff := make([]DiffLine, len(reversed))
+	for i := range reversed {
+		diff[i] = reversed[len(reversed)-1-i]
+	}
+	return diff
+}
+
+func replaceLines(a, b []string) []DiffLine {
+	diff := make([]DiffLine, 0, len(a)+len(b))
+	for _, line := range a {
+		diff = append(diff, DiffLine{Op: DiffDelete, Text: line})
+	}
+	for _, line := range b {
+		diff = append(diff, DiffLine{Op: DiffInsert, Text: line})
+	}
+	return diff
+}
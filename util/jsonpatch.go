@@ -0,0 +1,320 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ApplyMergePatch applies an RFC 7386 JSON merge patch to a JSON document.
+// Object members in the patch replace those in the document, null removes a
+// member, and any other patch value replaces the target outright.
+func ApplyMergePatch(document, patch []byte) ([]byte, error) {
+	var target, changes interface{}
+	if err := json.Unmarshal(document, &target); err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return nil, fmt.Errorf("invalid merge patch: %w", err)
+	}
+	return json.Marshal(mergePatch(target, changes))
+}
+
+func mergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+		} else {
+			targetObject[key] = mergePatch(targetObject[key], value)
+		}
+	}
+	return targetObject
+}
+
+// JSONPatchOperation is one operation of an RFC 6902 JSON patch
+type JSONPatchOperation struct {
+	Op    string           `json:"op"`
+	Path  *string          `json:"path"`
+	From  *string          `json:"from"`
+	Value *json.RawMessage `json:"value"`
+}
+
+// ApplyJSONPatch applies an RFC 6902 JSON patch to a JSON document. The
+// operations are applied in order and the whole patch fails if any of them
+// does, including a failed test operation.
+func ApplyJSONPatch(document, patch []byte) ([]byte, error) {
+	var target interface{}
+	if err := json.Unmarshal(document, &target); err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+	var operations []JSONPatchOperation
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return nil, fmt.Errorf("invalid JSON patch: %w", err)
+	}
+
+	for i, operation := range operations {
+		var err error
+		target, err = applyPatchOperation(target, operation)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s): %w", i, operation.Op, err)
+		}
+	}
+	return json.Marshal(target)
+}
+
+func applyPatchOperation(target interface{}, operation JSONPatchOperation) (interface{}, error) {
+	if operation.Path == nil {
+		return nil, errors.New("missing path")
+	}
+	path, err := parsePointer(*operation.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation.Op {
+	case "add", "replace", "test":
+		if operation.Value == nil {
+			return nil, errors.New("missing value")
+		}
+		var value interface{}
+		if err := json.Unmarshal(*operation.Value, &value); err != nil {
+			return nil, fmt.Errorf("invalid value: %w", err)
+		}
+		switch operation.Op {
+		case "add":
+			return pointerAdd(target, path, value)
+		case "replace":
+			if target, err = pointerRemove(target, path); err != nil {
+				return nil, err
+			}
+			return pointerAdd(target, path, value)
+		default:
+			current, err := pointerGet(target, path)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, errors.New("test failed")
+			}
+			return target, nil
+		}
+
+	case "remove":
+		return pointerRemove(target, path)
+
+	case "move", "copy":
+		if operation.From == nil {
+			return nil, errors.New("missing from")
+		}
+		from, err := parsePointer(*operation.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := pointerGet(target, from)
+		if err != nil {
+			return nil, err
+		}
+		if operation.Op == "move" {
+			if isPointerPrefix(from, path) && len(from) < len(path) {
+				return nil, errors.New("cannot move a value into itself")
+			}
+			if target, err = pointerRemove(target, from); err != nil {
+				return nil, err
+			}
+		} else {
+			// Copies must not share structure with the original
+			value = deepCopyJSON(value)
+		}
+		return pointerAdd(target, path, value)
+	}
+
+	return nil, fmt.Errorf("unknown operation %q", operation.Op)
+}
+
+// parsePointer splits an RFC 6901 JSON pointer into unescaped reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid pointer %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func isPointerPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// arrayIndex parses an array reference token. "-" refers to the position
+// after the last element and is only allowed when adding.
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if token == "-" && allowEnd {
+		return length, nil
+	}
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	limit := length - 1
+	if allowEnd {
+		limit = length
+	}
+	if index > limit {
+		return 0, fmt.Errorf("array index %d out of range", index)
+	}
+	return index, nil
+}
+
+func pointerGet(target interface{}, path []string) (interface{}, error) {
+	current := target
+	for _, token := range path {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("path member %q does not exist", token)
+			}
+			current = value
+		case []interface{}:
+			index, err := arrayIndex(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("cannot reference %q in a scalar value", token)
+		}
+	}
+	return current, nil
+}
+
+// pointerAdd returns target with value added at path. Arrays may be
+// reallocated, so the returned document must be used in place of target.
+func pointerAdd(target interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := pointerGet(target, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[last] = value
+		return target, nil
+	case []interface{}:
+		index, err := arrayIndex(last, len(node), true)
+		if err != nil {
+			return nil, err
+		}
+		grown := make([]interface{}, 0, len(node)+1)
+		grown = append(grown, node[:index]...)
+		grown = append(grown, value)
+		grown = append(grown, node[index:]...)
+		return replaceAt(target, path[:len(path)-1], grown)
+	}
+	return nil, fmt.Errorf("cannot add %q to a scalar value", last)
+}
+
+// pointerRemove returns target with the value at path removed
+func pointerRemove(target interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+
+	parent, err := pointerGet(target, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		if _, ok := node[last]; !ok {
+			return nil, fmt.Errorf("path member %q does not exist", last)
+		}
+		delete(node, last)
+		return target, nil
+	case []interface{}:
+		index, err := arrayIndex(last, len(node), false)
+		if err != nil {
+			return nil, err
+		}
+		shrunk := make([]interface{}, 0, len(node)-1)
+		shrunk = append(shrunk, node[:index]...)
+		shrunk = append(shrunk, node[index+1:]...)
+		return replaceAt(target, path[:len(path)-1], shrunk)
+	}
+	return nil, fmt.Errorf("cannot remove %q from a scalar value", last)
+}
+
+// replaceAt stores value at path, which must already exist
+func replaceAt(target interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := pointerGet(target, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[last] = value
+	case []interface{}:
+		index, err := arrayIndex(last, len(node), false)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = value
+	}
+	return target, nil
+}
+
+func deepCopyJSON(value interface{}) interface{} {
+	switch node := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(node))
+		for key, child := range node {
+			copied[key] = deepCopyJSON(child)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(node))
+		for i, child := range node {
+			copied[i] = deepCopyJSON(child)
+		}
+		return copied
+	}
+	return value
+}
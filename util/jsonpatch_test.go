@@ -0,0 +1,158 @@
+package util
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// jsonEqual reports whether two JSON texts hold the same value
+func jsonEqual(t *testing.T, a, b []byte) bool {
+	t.Helper()
+	var x, y interface{}
+	if err := json.Unmarshal(a, &x); err != nil {
+		t.Fatalf("invalid JSON %s: %v", a, err)
+	}
+	if err := json.Unmarshal(b, &y); err != nil {
+		t.Fatalf("invalid JSON %s: %v", b, err)
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	// The examples of RFC 6902 appendix A, plus edge cases of our own. An
+	// empty want means the patch must fail.
+	tests := []struct {
+		name     string
+		document string
+		patch    string
+		want     string
+	}{
+		{"A.1 add object member", `{"foo":"bar"}`,
+			`[{"op":"add","path":"/baz","value":"qux"}]`,
+			`{"baz":"qux","foo":"bar"}`},
+		{"A.2 add array element", `{"foo":["bar","baz"]}`,
+			`[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			`{"foo":["bar","qux","baz"]}`},
+		{"A.3 remove object member", `{"baz":"qux","foo":"bar"}`,
+			`[{"op":"remove","path":"/baz"}]`,
+			`{"foo":"bar"}`},
+		{"A.4 remove array element", `{"foo":["bar","qux","baz"]}`,
+			`[{"op":"remove","path":"/foo/1"}]`,
+			`{"foo":["bar","baz"]}`},
+		{"A.5 replace value", `{"baz":"qux","foo":"bar"}`,
+			`[{"op":"replace","path":"/baz","value":"boo"}]`,
+			`{"baz":"boo","foo":"bar"}`},
+		{"A.6 move value", `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			`[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{"A.7 move array element", `{"foo":["all","grass","cows","eat"]}`,
+			`[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			`{"foo":["all","cows","eat","grass"]}`},
+		{"A.8 test success", `{"baz":"qux","foo":["a",2,"c"]}`,
+			`[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+			`{"baz":"qux","foo":["a",2,"c"]}`},
+		{"A.9 test failure", `{"baz":"qux"}`,
+			`[{"op":"test","path":"/baz","value":"bar"}]`,
+			``},
+		{"A.10 add nested member", `{"foo":"bar"}`,
+			`[{"op":"add","path":"/child","value":{"grandchild":{}}}]`,
+			`{"foo":"bar","child":{"grandchild":{}}}`},
+		{"A.11 ignore unrecognized members", `{"foo":"bar"}`,
+			`[{"op":"add","path":"/baz","value":"qux","xyz":123}]`,
+			`{"foo":"bar","baz":"qux"}`},
+		{"A.12 add to nonexistent target", `{"foo":"bar"}`,
+			`[{"op":"add","path":"/baz/bat","value":"qux"}]`,
+			``},
+		{"A.14 escape ordering", `{"/":9,"~1":10}`,
+			`[{"op":"test","path":"/~01","value":10}]`,
+			`{"/":9,"~1":10}`},
+		{"A.15 strings are not numbers", `{"/":9,"~1":10}`,
+			`[{"op":"test","path":"/~01","value":"10"}]`,
+			``},
+		{"A.16 add array value", `{"foo":["bar"]}`,
+			`[{"op":"add","path":"/foo/-","value":["abc","def"]}]`,
+			`{"foo":["bar",["abc","def"]]}`},
+		{"dash index appends", `{"tags":["a","b"]}`,
+			`[{"op":"add","path":"/tags/-","value":"c"}]`,
+			`{"tags":["a","b","c"]}`},
+		{"dash index only when adding", `{"tags":["a","b"]}`,
+			`[{"op":"remove","path":"/tags/-"}]`,
+			``},
+		{"index past the end", `{"tags":["a"]}`,
+			`[{"op":"add","path":"/tags/2","value":"c"}]`,
+			``},
+		{"leading zero index", `{"tags":["a","b"]}`,
+			`[{"op":"remove","path":"/tags/01"}]`,
+			``},
+		{"move into itself", `{"a":{"b":{}}}`,
+			`[{"op":"move","from":"/a","path":"/a/b/c"}]`,
+			``},
+		{"move onto itself", `{"a":1}`,
+			`[{"op":"move","from":"/a","path":"/a"}]`,
+			`{"a":1}`},
+		{"copy does not share", `{"a":{"x":1}}`,
+			`[{"op":"copy","from":"/a","path":"/b"},{"op":"replace","path":"/b/x","value":2}]`,
+			`{"a":{"x":1},"b":{"x":2}}`},
+		{"replace missing member", `{"a":1}`,
+			`[{"op":"replace","path":"/b","value":2}]`,
+			``},
+		{"failed operation undoes the patch", `{"a":1}`,
+			`[{"op":"add","path":"/b","value":2},{"op":"test","path":"/a","value":2}]`,
+			``},
+		{"unknown operation", `{"a":1}`,
+			`[{"op":"frobnicate","path":"/a"}]`,
+			``},
+		{"missing value", `{"a":1}`,
+			`[{"op":"add","path":"/b"}]`,
+			``},
+	}
+	for _, tt := range tests {
+		got, err := ApplyJSONPatch([]byte(tt.document), []byte(tt.patch))
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %s", tt.name, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if !jsonEqual(t, got, []byte(tt.want)) {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	tests := []struct {
+		document string
+		patch    string
+		want     string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, tt := range tests {
+		got, err := ApplyMergePatch([]byte(tt.document), []byte(tt.patch))
+		if err != nil {
+			t.Errorf("ApplyMergePatch(%s, %s): unexpected error: %v", tt.document, tt.patch, err)
+			continue
+		}
+		if !jsonEqual(t, got, []byte(tt.want)) {
+			t.Errorf("ApplyMergePatch(%s, %s) = %s, want %s", tt.document, tt.patch, got, tt.want)
+		}
+	}
+
+	if _, err := ApplyMergePatch([]byte(`{}`), []byte(`{`)); err == nil {
+		t.Error("expected an error for an invalid patch")
+	}
+}
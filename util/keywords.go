@@ -0,0 +1,110 @@
+package util
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Keyword extraction limits
+const (
+	minKeywordLength = 3
+	maxKeywordLength = 32
+	// maxKeywordTerms bounds the distinct terms kept per document; the
+	// rarest ones are dropped first
+	maxKeywordTerms = 500
+	// titleWeight is how many occurrences in the body a word in the title
+	// counts as
+	titleWeight = 3
+)
+
+// Keyword is a term of a document with its TF-IDF score
+type Keyword struct {
+	Term  string
+	Score float64
+}
+
+// KeywordTerms counts the candidate keywords of a document: its words
+// without stop words, numbers and very short or long words. Words in the
+// title weigh more than words in the text.
+func KeywordTerms(title, text string) map[string]int {
+	terms := map[string]int{}
+	addKeywordTerms(terms, title, titleWeight)
+	addKeywordTerms(terms, text, 1)
+	if len(terms) <= maxKeywordTerms {
+		return terms
+	}
+
+	ranked := make([]string, 0, len(terms))
+	for term := range terms {
+		ranked = append(ranked, term)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if terms[ranked[i]] != terms[ranked[j]] {
+			return terms[ranked[i]] > terms[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+	for _, term := range ranked[maxKeywordTerms:] {
+		delete(terms, term)
+	}
+	return terms
+}
+
+func addKeywordTerms(terms map[string]int, text string, weight int) {
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		length := len([]rune(word))
+		if length < minKeywordLength || length > maxKeywordLength || stopWords[word] || isNumber(word) {
+			continue
+		}
+		terms[word] += weight
+	}
+}
+
+func isNumber(word string) bool {
+	for _, r := range word {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// RankKeywords scores the terms of a document by TF-IDF against a corpus of
+// the given number of documents, where documentFrequencies holds the number
+// of documents each term occurs in. Terms common across the corpus score
+// low even when frequent in the document. The best limit keywords are
+// returned, highest score first.
+func RankKeywords(terms map[string]int, documentFrequencies map[string]int64, documents int64, limit int) []Keyword {
+	total := 0
+	for _, count := range terms {
+		total += count
+	}
+	if total == 0 || limit <= 0 {
+		return nil
+	}
+
+	keywords := make([]Keyword, 0, len(terms))
+	for term, count := range terms {
+		// Smoothed so that terms unknown to the corpus do not divide by
+		// zero and terms found in every document still count a little
+		idf := math.Log(float64(1+documents)/float64(1+documentFrequencies[term])) + 1
+		keywords = append(keywords, Keyword{
+			Term:  term,
+			Score: float64(count) / float64(total) * idf,
+		})
+	}
+	sort.Slice(keywords, func(i, j int) bool {
+		if keywords[i].Score != keywords[j].Score {
+			return keywords[i].Score > keywords[j].Score
+		}
+		return keywords[i].Term < keywords[j].Term
+	})
+	if len(keywords) > limit {
+		keywords = keywords[:limit]
+	}
+	return keywords
+}
@@ -0,0 +1,71 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeywordTerms(t *testing.T) {
+	got := KeywordTerms("Postgres indexes", "The indexes of a table: 2024 was the year of Postgres, and of B-tree indexes.")
+	want := map[string]int{
+		"postgres": titleWeight + 1,
+		"indexes":  titleWeight + 2,
+		"table":    1,
+		"year":     1,
+		"tree":     1,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("KeywordTerms = %v, want %v", got, want)
+	}
+
+	if got := KeywordTerms("", "a an the 42 ok"); len(got) != 0 {
+		t.Errorf("KeywordTerms of stop words, numbers and short words = %v, want none", got)
+	}
+}
+
+func TestKeywordTermsLimit(t *testing.T) {
+	text := sampleText("term", maxKeywordTerms+100) + " frequent frequent"
+	terms := KeywordTerms("", text)
+	if len(terms) != maxKeywordTerms {
+		t.Fatalf("KeywordTerms kept %d terms, want %d", len(terms), maxKeywordTerms)
+	}
+	if terms["frequent"] != 2 {
+		t.Errorf("KeywordTerms dropped the most frequent term")
+	}
+}
+
+func TestRankKeywords(t *testing.T) {
+	terms := map[string]int{"golang": 3, "code": 3, "generics": 1}
+	frequencies := map[string]int64{"code": 90, "golang": 5}
+
+	tests := []struct {
+		name  string
+		limit int
+		want  []string
+	}{
+		// Terms common across the corpus rank below rarer ones of the same
+		// frequency; terms unknown to the corpus weigh the most
+		{"all", 10, []string{"golang", "generics", "code"}},
+		{"limited", 1, []string{"golang"}},
+		{"none", 0, nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, keyword := range RankKeywords(terms, frequencies, 100, tt.limit) {
+			got = append(got, keyword.Term)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: RankKeywords = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Without a corpus, ties are broken alphabetically
+	ranked := RankKeywords(map[string]int{"beta": 1, "alpha": 1}, nil, 0, 2)
+	if len(ranked) != 2 || ranked[0].Term != "alpha" || ranked[0].Score != ranked[1].Score {
+		t.Errorf("RankKeywords without a corpus = %v", ranked)
+	}
+
+	if got := RankKeywords(nil, nil, 10, 5); got != nil {
+		t.Errorf("RankKeywords of no terms = %v, want nil", got)
+	}
+}
@@ -0,0 +1,783 @@
+package util
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	atxHeadingPattern    = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	fencePattern         = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})[ \t]*([^`\\s]*)")
+	thematicPattern      = regexp.MustCompile(`^ {0,3}((\*[ \t]*){3,}|(-[ \t]*){3,}|(_[ \t]*){3,})$`)
+	setextPattern        = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	listItemPattern      = regexp.MustCompile(`^( {0,3})([-*+]|[0-9]{1,9}[.)])( +|$)`)
+	tableDelimPattern    = regexp.MustCompile(`^ {0,3}\|?[ \t]*:?-+:?[ \t]*(\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+	blockquotePattern    = regexp.MustCompile(`^ {0,3}> ?`)
+	inlineLinkPattern    = regexp.MustCompile(`^\(\s*<?([^\s()<>]*)>?(?:\s+"([^"]*)")?\s*\)`)
+	autolinkPattern      = regexp.MustCompile(`^<((?:https?|mailto):[^\s<>]+)>`)
+	escapablePunctuation = "\\`*_{}[]()#+-.!|~<>\""
+)
+
+// RenderMarkdown converts Markdown to HTML. It supports the CommonMark block
+// structure most editors produce plus the GFM extensions for tables and
+// strikethrough. Headings get anchor ids and fenced code keeps its language
+// as a class. Raw HTML is escaped; the result should still be passed through
+// SanitizeHTML before it is served.
+func RenderMarkdown(source string) string {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	source = strings.ReplaceAll(source, "\t", "    ")
+
+	r := &markdownRenderer{anchors: map[string]int{}}
+	r.renderBlocks(strings.Split(source, "\n"), false)
+	return r.builder.String()
+}
+
+// maxMarkdownNesting bounds how deeply blockquotes and lists nest. Each
+// level renders its lines again, so deeper markers are kept as text.
+const maxMarkdownNesting = 16
+
+type markdownRenderer struct {
+	builder strings.Builder
+	anchors map[string]int
+	depth   int
+}
+
+// renderBlocks renders a sequence of lines as block elements. In tight list
+// items paragraphs are written without <p> wrappers.
+func (r *markdownRenderer) renderBlocks(lines []string, tight bool) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+
+		case fencePattern.MatchString(line):
+			i = r.renderFencedCode(lines, i)
+
+		case atxHeadingPattern.MatchString(line):
+			match := atxHeadingPattern.FindStringSubmatch(line)
+			r.renderHeading(len(match[1]), match[2])
+			i++
+
+		case thematicPattern.MatchString(line):
+			r.builder.WriteString("<hr>\n")
+			i++
+
+		case r.depth < maxMarkdownNesting && blockquotePattern.MatchString(line):
+			i = r.renderBlockquote(lines, i)
+
+		case r.depth < maxMarkdownNesting && listItemPattern.MatchString(line):
+			i = r.renderList(lines, i)
+
+		case strings.HasPrefix(line, "    "):
+			i = r.renderIndentedCode(lines, i)
+
+		case i+1 < len(lines) && strings.Contains(line, "|") && tableDelimPattern.MatchString(lines[i+1]):
+			i = r.renderTable(lines, i)
+
+		default:
+			i = r.renderParagraph(lines, i, tight)
+		}
+	}
+}
+
+func (r *markdownRenderer) renderHeading(level int, text string) {
+	content := r.renderInline(strings.TrimSpace(text))
+	anchor := r.anchorFor(text)
+	fmt.Fprintf(&r.builder, "<h%d id=\"%s\">%s</h%d>\n", level, anchor, content, level)
+}
+
+// anchorFor derives a unique heading id from the heading text
+func (r *markdownRenderer) anchorFor(text string) string {
+	base := Slugify(stripInlineMarkup(text))
+	if base == "" {
+		base = "section"
+	}
+	count := r.anchors[base]
+	r.anchors[base] = count + 1
+	if count == 0 {
+		return base
+	}
+	return fmt.Sprintf("%s-%d", base, count)
+}
+
+func (r *markdownRenderer) renderFencedCode(lines []string, start int) int {
+	match := fencePattern.FindStringSubmatch(lines[start])
+	fence := match[1]
+	language := strings.ToLower(match[2])
+
+	var code []string
+	i := start + 1
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			i++
+			break
+		}
+		code = append(code, lines[i])
+	}
+
+	r.builder.WriteString("<pre><code")
+	if language != "" {
+		r.builder.WriteString(` class="language-` + html.EscapeString(language) + `"`)
+	}
+	r.builder.WriteString(">")
+	for _, line := range code {
+		r.builder.WriteString(html.EscapeString(line) + "\n")
+	}
+	r.builder.WriteString("</code></pre>\n")
+	return i
+}
+
+func (r *markdownRenderer) renderIndentedCode(lines []string, start int) int {
+	var code []string
+	i := start
+	for ; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "    ") {
+			code = append(code, lines[i][4:])
+		} else if strings.TrimSpace(lines[i]) == "" {
+			code = append(code, "")
+		} else {
+			break
+		}
+	}
+	// Trailing blank lines belong to whatever follows
+	for len(code) > 0 && code[len(code)-1] == "" {
+		code = code[:len(code)-1]
+	}
+
+	r.builder.WriteString("<pre><code>")
+	for _, line := range code {
+		r.builder.WriteString(html.EscapeString(line) + "\n")
+	}
+	r.builder.WriteString("</code></pre>\n")
+	return i
+}
+
+func (r *markdownRenderer) renderBlockquote(lines []string, start int) int {
+	var inner []string
+	i := start
+	for ; i < len(lines); i++ {
+		if blockquotePattern.MatchString(lines[i]) {
+			inner = append(inner, blockquotePattern.ReplaceAllString(lines[i], ""))
+		} else if strings.TrimSpace(lines[i]) != "" && len(inner) > 0 && strings.TrimSpace(inner[len(inner)-1]) != "" {
+			// Lazy continuation of a quoted paragraph
+			inner = append(inner, lines[i])
+		} else {
+			break
+		}
+	}
+
+	r.builder.WriteString("<blockquote>\n")
+	r.depth++
+	r.renderBlocks(inner, false)
+	r.depth--
+	r.builder.WriteString("</blockquote>\n")
+	return i
+}
+
+func (r *markdownRenderer) renderList(lines []string, start int) int {
+	first := listItemPattern.FindStringSubmatch(lines[start])
+	ordered := !strings.ContainsAny(first[2][:1], "-*+")
+	marker := first[2][len(first[2])-1:]
+	sameListType := func(itemMarker string) bool {
+		if ordered {
+			return strings.HasSuffix(itemMarker, marker) && !strings.ContainsAny(itemMarker, "-*+")
+		}
+		return itemMarker == first[2]
+	}
+
+	var items [][]string
+	tight := true
+	i := start
+	for i < len(lines) {
+		match := listItemPattern.FindStringSubmatch(lines[i])
+		if match == nil {
+			break
+		}
+		if !sameListType(match[2]) {
+			break
+		}
+
+		// Continuation lines must be indented to the item's content column
+		indent := len(match[0])
+		if match[3] == "" || len(match[3]) > 4 {
+			indent = len(match[1]) + len(match[2]) + 1
+		}
+		item := []string{strings.TrimLeft(lines[i][min(len(lines[i]), len(match[1])+len(match[2])):], " ")}
+		i++
+
+		for i < len(lines) {
+			line := lines[i]
+			if strings.TrimSpace(line) == "" {
+				// A blank line ends the item unless indented content follows
+				if i+1 < len(lines) && leadingSpaces(lines[i+1]) >= indent {
+					item = append(item, "")
+					i++
+					continue
+				}
+				break
+			}
+			if leadingSpaces(line) >= indent {
+				item = append(item, line[indent:])
+				i++
+				continue
+			}
+			if listItemPattern.MatchString(line) || !isParagraphContinuation(line) {
+				break
+			}
+			// Lazy continuation of the item's paragraph
+			item = append(item, strings.TrimLeft(line, " "))
+			i++
+		}
+
+		for _, line := range item {
+			if line == "" {
+				tight = false
+			}
+		}
+		items = append(items, item)
+
+		// Blank lines between items make the list loose
+		if i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+			next := i
+			for next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+				next++
+			}
+			if next < len(lines) {
+				if following := listItemPattern.FindStringSubmatch(lines[next]); following != nil && sameListType(following[2]) {
+					tight = false
+					i = next
+				}
+			}
+		}
+	}
+
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+	}
+	r.builder.WriteString("<" + tag)
+	if ordered {
+		startNumber := strings.TrimRight(first[2], ".)")
+		if strings.TrimLeft(startNumber, "0") != "1" {
+			r.builder.WriteString(` start="` + strings.TrimLeft(startNumber, "0") + `"`)
+		}
+	}
+	r.builder.WriteString(">\n")
+	r.depth++
+	for _, item := range items {
+		r.builder.WriteString("<li>")
+		r.renderBlocks(item, tight)
+		r.builder.WriteString("</li>\n")
+	}
+	r.depth--
+	r.builder.WriteString("</" + tag + ">\n")
+	return i
+}
+
+func (r *markdownRenderer) renderTable(lines []string, start int) int {
+	headers := splitTableRow(lines[start])
+	delimiters := splitTableRow(lines[start+1])
+
+	alignments := make([]string, len(headers))
+	for col := range headers {
+		if col >= len(delimiters) {
+			break
+		}
+		cell := strings.TrimSpace(delimiters[col])
+		left, right := strings.HasPrefix(cell, ":"), strings.HasSuffix(cell, ":")
+		switch {
+		case left && right:
+			alignments[col] = "center"
+		case right:
+			alignments[col] = "right"
+		case left:
+			alignments[col] = "left"
+		}
+	}
+
+	r.builder.WriteString("<table>\n<thead>\n<tr>\n")
+	for col, header := range headers {
+		r.writeTableCell("th", alignments[col], header)
+	}
+	r.builder.WriteString("</tr>\n</thead>\n")
+
+	i := start + 2
+	if i < len(lines) && strings.TrimSpace(lines[i]) != "" {
+		r.builder.WriteString("<tbody>\n")
+		for ; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "" || !strings.Contains(lines[i], "|") {
+				break
+			}
+			cells := splitTableRow(lines[i])
+			r.builder.WriteString("<tr>\n")
+			for col := range headers {
+				cell := ""
+				if col < len(cells) {
+					cell = cells[col]
+				}
+				r.writeTableCell("td", alignments[col], cell)
+			}
+			r.builder.WriteString("</tr>\n")
+		}
+		r.builder.WriteString("</tbody>\n")
+	}
+	r.builder.WriteString("</table>\n")
+	return i
+}
+
+func (r *markdownRenderer) writeTableCell(tag, align, content string) {
+	r.builder.WriteString("<" + tag)
+	if align != "" {
+		r.builder.WriteString(` align="` + align + `"`)
+	}
+	r.builder.WriteString(">" + r.renderInline(strings.TrimSpace(content)) + "</" + tag + ">\n")
+}
+
+// splitTableRow splits a table row on unescaped pipes, ignoring the optional
+// leading and trailing pipe
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' && i+1 < len(line) && line[i+1] == '|' {
+			cell.WriteByte('|')
+			i++
+			continue
+		}
+		if line[i] == '|' {
+			cells = append(cells, cell.String())
+			cell.Reset()
+			continue
+		}
+		cell.WriteByte(line[i])
+	}
+	return append(cells, cell.String())
+}
+
+func (r *markdownRenderer) renderParagraph(lines []string, start int, tight bool) int {
+	var text []string
+	i := start
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			break
+		}
+		if i > start {
+			// Setext heading underline turns the paragraph into a heading
+			if setextPattern.MatchString(line) {
+				level := 2
+				if strings.Contains(line, "=") {
+					level = 1
+				}
+				r.renderHeading(level, strings.Join(text, " "))
+				return i + 1
+			}
+			if !isParagraphContinuation(line) {
+				break
+			}
+		}
+		text = append(text, strings.TrimLeft(line, " "))
+	}
+
+	content := r.renderInline(strings.Join(text, "\n"))
+	if tight {
+		r.builder.WriteString(content)
+	} else {
+		r.builder.WriteString("<p>" + content + "</p>\n")
+	}
+	return i
+}
+
+// isParagraphContinuation reports whether a line can continue a paragraph
+// rather than start a new block
+func isParagraphContinuation(line string) bool {
+	return !fencePattern.MatchString(line) &&
+		!atxHeadingPattern.MatchString(line) &&
+		!listItemPattern.MatchString(line) &&
+		!thematicPattern.MatchString(line) &&
+		!blockquotePattern.MatchString(line)
+}
+
+func leadingSpaces(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// renderInline renders emphasis, code spans, links, images and line breaks
+// within a block, escaping everything else
+func (r *markdownRenderer) renderInline(text string) string {
+	return r.renderInlineSpan(text, true)
+}
+
+// inlineNode is a piece of rendered inline content. Runs of emphasis
+// delimiters stay separate nodes until processEmphasis has matched them.
+type inlineNode struct {
+	html string
+
+	// Delimiter runs only: the delimiter character, the number of
+	// delimiters left and the original run length
+	delim    byte
+	count    int
+	length   int
+	canOpen  bool
+	canClose bool
+	// Tags opened after and closed before the remaining delimiters
+	openTags  string
+	closeTags string
+}
+
+// renderInlineSpan renders inline content in a single left-to-right pass.
+// Links are not parsed inside link labels, so labels are rendered once.
+func (r *markdownRenderer) renderInlineSpan(text string, links bool) string {
+	var nodes []inlineNode
+	var pending strings.Builder
+	flush := func() {
+		if pending.Len() > 0 {
+			nodes = append(nodes, inlineNode{html: pending.String()})
+			pending.Reset()
+		}
+	}
+
+	brackets := matchBrackets(text)
+	// Backtick run lengths with no closing run left in the text
+	unclosedCode := map[int]bool{}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '\\' && i+1 < len(text) && text[i+1] == '\n':
+			pending.WriteString("<br>\n")
+			i += 2
+
+		case c == '\\' && i+1 < len(text) && strings.IndexByte(escapablePunctuation, text[i+1]) >= 0:
+			pending.WriteString(html.EscapeString(text[i+1 : i+2]))
+			i += 2
+
+		case c == '`':
+			run := countRun(text, i, '`')
+			closing := -1
+			if !unclosedCode[run] {
+				closing = closingBackticks(text[i+run:], run)
+			}
+			if closing < 0 {
+				unclosedCode[run] = true
+				pending.WriteString(strings.Repeat("`", run))
+				i += run
+				continue
+			}
+			code := strings.ReplaceAll(text[i+run:i+run+closing], "\n", " ")
+			if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' {
+				code = code[1 : len(code)-1]
+			}
+			pending.WriteString("<code>" + html.EscapeString(code) + "</code>")
+			i += run + closing + run
+
+		case c == '!' && i+1 < len(text) && text[i+1] == '[':
+			if label, href, title, next, ok := parseLink(text, i+1, brackets); ok {
+				pending.WriteString(`<img src="` + html.EscapeString(href) + `" alt="` + html.EscapeString(stripInlineMarkup(label)) + `"`)
+				if title != "" {
+					pending.WriteString(` title="` + html.EscapeString(title) + `"`)
+				}
+				pending.WriteString(">")
+				i = next
+				continue
+			}
+			pending.WriteString("!")
+			i++
+
+		case c == '[' && links:
+			if label, href, title, next, ok := parseLink(text, i, brackets); ok {
+				pending.WriteString(`<a href="` + html.EscapeString(href) + `"`)
+				if title != "" {
+					pending.WriteString(` title="` + html.EscapeString(title) + `"`)
+				}
+				pending.WriteString(">" + r.renderInlineSpan(label, false) + "</a>")
+				i = next
+				continue
+			}
+			pending.WriteString("[")
+			i++
+
+		case c == '<' && autolinkPattern.MatchString(text[i:]):
+			match := autolinkPattern.FindStringSubmatch(text[i:])
+			link := html.EscapeString(match[1])
+			pending.WriteString(`<a href="` + link + `">` + link + "</a>")
+			i += len(match[0])
+
+		case c == '*' || c == '_' || c == '~':
+			run := countRun(text, i, c)
+			// Only double tildes strike through
+			if c == '~' && run != 2 {
+				pending.WriteString(text[i : i+run])
+				i += run
+				continue
+			}
+			flush()
+			nodes = append(nodes, newDelimiterRun(text, i, run))
+			i += run
+
+		case c == ' ':
+			// Two trailing spaces before a newline make a hard break
+			run := countRun(text, i, ' ')
+			if run >= 2 && i+run < len(text) && text[i+run] == '\n' {
+				pending.WriteString("<br>")
+			} else {
+				pending.WriteString(text[i : i+run])
+			}
+			i += run
+
+		default:
+			pending.WriteString(html.EscapeString(text[i : i+1]))
+			i++
+		}
+	}
+	flush()
+
+	processEmphasis(nodes)
+
+	var out strings.Builder
+	for _, node := range nodes {
+		if node.delim == 0 {
+			out.WriteString(node.html)
+			continue
+		}
+		out.WriteString(node.closeTags)
+		out.WriteString(strings.Repeat(string(node.delim), node.count))
+		out.WriteString(node.openTags)
+	}
+	return out.String()
+}
+
+// closingBackticks finds the first run of exactly run backticks in text,
+// which closes a code span, or returns -1
+func closingBackticks(text string, run int) int {
+	for i := 0; i < len(text); {
+		offset := strings.IndexByte(text[i:], '`')
+		if offset < 0 {
+			return -1
+		}
+		i += offset
+		length := countRun(text, i, '`')
+		if length == run {
+			return i
+		}
+		i += length
+	}
+	return -1
+}
+
+// newDelimiterRun classifies the run of delimiters at text[start:start+run]
+// by whether it can open or close emphasis, following the CommonMark
+// flanking rules. Underscores inside words are literal, as in snake_case.
+func newDelimiterRun(text string, start, run int) inlineNode {
+	before, after := byte(' '), byte(' ')
+	if start > 0 {
+		before = text[start-1]
+	}
+	if start+run < len(text) {
+		after = text[start+run]
+	}
+	leftFlanking := !isSpaceByte(after) &&
+		(!isPunctuationByte(after) || isSpaceByte(before) || isPunctuationByte(before))
+	rightFlanking := !isSpaceByte(before) &&
+		(!isPunctuationByte(before) || isSpaceByte(after) || isPunctuationByte(after))
+
+	node := inlineNode{
+		delim:    text[start],
+		count:    run,
+		length:   run,
+		canOpen:  leftFlanking,
+		canClose: rightFlanking,
+	}
+	if node.delim == '_' {
+		node.canOpen = leftFlanking && (!rightFlanking || isPunctuationByte(before))
+		node.canClose = rightFlanking && (!leftFlanking || isPunctuationByte(after))
+	}
+	return node
+}
+
+// processEmphasis matches delimiter runs into <em>, <strong> and <del>
+// with the CommonMark delimiter stack algorithm. Each closer looks back for
+// the nearest opener of the same kind; the lowest point a failed search
+// reached is remembered per kind, so the pass stays linear.
+func processEmphasis(nodes []inlineNode) {
+	// The delimiter stack as links between node indices, -1 for none
+	prev := make([]int, len(nodes))
+	next := make([]int, len(nodes))
+	first, last := -1, -1
+	for i := range nodes {
+		if nodes[i].delim == 0 {
+			continue
+		}
+		prev[i], next[i] = last, -1
+		if last >= 0 {
+			next[last] = i
+		} else {
+			first = i
+		}
+		last = i
+	}
+	remove := func(i int) {
+		if prev[i] >= 0 {
+			next[prev[i]] = next[i]
+		} else {
+			first = next[i]
+		}
+		if next[i] >= 0 {
+			prev[next[i]] = prev[i]
+		}
+	}
+
+	type openerKind struct {
+		delim   byte
+		canOpen bool
+		length  int
+	}
+	openersBottom := map[openerKind]int{}
+
+	for closer := first; closer >= 0; {
+		c := &nodes[closer]
+		if !c.canClose {
+			closer = next[closer]
+			continue
+		}
+
+		kind := openerKind{c.delim, c.canOpen, c.length % 3}
+		bottom, seen := openersBottom[kind]
+		if !seen {
+			bottom = -1
+		}
+		opener := prev[closer]
+		for ; opener > bottom; opener = prev[opener] {
+			if o := &nodes[opener]; o.delim == c.delim && o.canOpen && emphasisMatches(o, c) {
+				break
+			}
+		}
+
+		if opener <= bottom {
+			openersBottom[kind] = prev[closer]
+			following := next[closer]
+			if !c.canOpen {
+				remove(closer)
+			}
+			closer = following
+			continue
+		}
+
+		o := &nodes[opener]
+		used := 1
+		if o.count >= 2 && c.count >= 2 {
+			used = 2
+		}
+		open, close := "<em>", "</em>"
+		switch {
+		case c.delim == '~':
+			open, close = "<del>", "</del>"
+		case used == 2:
+			open, close = "<strong>", "</strong>"
+		}
+		o.count -= used
+		c.count -= used
+		o.openTags = open + o.openTags
+		c.closeTags += close
+
+		// Delimiters between the pair can no longer be matched
+		for between := next[opener]; between != closer; between = next[between] {
+			remove(between)
+		}
+		if o.count == 0 {
+			remove(opener)
+		}
+		if c.count == 0 {
+			following := next[closer]
+			remove(closer)
+			closer = following
+		}
+	}
+}
+
+// emphasisMatches applies the CommonMark "rule of 3": a run that can both
+// open and close cannot pair with another when their lengths add up to a
+// multiple of three, unless both lengths are
+func emphasisMatches(opener, closer *inlineNode) bool {
+	if opener.delim == '~' {
+		return true
+	}
+	if (opener.canOpen && opener.canClose) || (closer.canOpen && closer.canClose) {
+		return (opener.length+closer.length)%3 != 0 ||
+			(opener.length%3 == 0 && closer.length%3 == 0)
+	}
+	return true
+}
+
+// matchBrackets pairs the position of each [ with that of its closing ],
+// skipping escaped brackets
+func matchBrackets(text string) map[int]int {
+	pairs := map[int]int{}
+	var open []int
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '[':
+			open = append(open, i)
+		case ']':
+			if len(open) > 0 {
+				pairs[open[len(open)-1]] = i
+				open = open[:len(open)-1]
+			}
+		}
+	}
+	return pairs
+}
+
+// parseLink parses [label](href "title") starting at the opening bracket,
+// using the bracket pairs found by matchBrackets
+func parseLink(text string, start int, brackets map[int]int) (label, href, title string, next int, ok bool) {
+	end, found := brackets[start]
+	if !found {
+		return "", "", "", 0, false
+	}
+	match := inlineLinkPattern.FindStringSubmatch(text[end+1:])
+	if match == nil {
+		return "", "", "", 0, false
+	}
+	return text[start+1 : end], match[1], match[2], end + 1 + len(match[0]), true
+}
+
+// stripInlineMarkup removes common inline markup characters, leaving text
+// suitable for alt attributes and anchors
+func stripInlineMarkup(text string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune("*_~`[]", r) {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+func countRun(text string, i int, c byte) int {
+	n := 0
+	for i+n < len(text) && text[i+n] == c {
+		n++
+	}
+	return n
+}
+
+func isSpaceByte(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+func isPunctuationByte(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
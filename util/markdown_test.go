@@ -0,0 +1,85 @@
+package util
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderMarkdownInline(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"emphasis", "*a*", "<p><em>a</em></p>\n"},
+		{"strong", "**a**", "<p><strong>a</strong></p>\n"},
+		{"strong emphasis", "***a***", "<p><em><strong>a</strong></em></p>\n"},
+		{"underscore emphasis", "_a_ __b__", "<p><em>a</em> <strong>b</strong></p>\n"},
+		{"strikethrough", "~~a~~", "<p><del>a</del></p>\n"},
+		{"single tilde is literal", "~a~", "<p>~a~</p>\n"},
+		{"nested emphasis", "**a *b* c**", "<p><strong>a <em>b</em> c</strong></p>\n"},
+		{"leftover opener", "**a*", "<p>*<em>a</em></p>\n"},
+		{"intraword asterisks", "a*b*c", "<p>a<em>b</em>c</p>\n"},
+		{"intraword underscores", "snake_case_name", "<p>snake_case_name</p>\n"},
+		{"opener before space", "* a*", "<ul>\n<li>a*</li>\n</ul>\n"},
+		{"unmatched delimiters", "*a _b", "<p>*a _b</p>\n"},
+		{"rule of three", "*a**b*", "<p><em>a**b</em></p>\n"},
+		{"escaped delimiter", `\*a\*`, "<p>*a*</p>\n"},
+		{"code span", "`*a*` `b`", "<p><code>*a*</code> <code>b</code></p>\n"},
+		{"unclosed code span", "`a ``b``", "<p>`a <code>b</code></p>\n"},
+		{"emphasis does not cross code", "*a `b*` c*", "<p><em>a <code>b*</code> c</em></p>\n"},
+		{"link", `[a *b*](http://x.test "t")`, `<p><a href="http://x.test" title="t">a <em>b</em></a></p>` + "\n"},
+		{"emphasis does not cross links", "*[a*](u)", "<p>*<a href=\"u\">a*</a></p>\n"},
+		{"unmatched bracket", "[a [b](u)", "<p>[a <a href=\"u\">b</a></p>\n"},
+		{"image", "![*a*](i.png)", `<p><img src="i.png" alt="a"></p>` + "\n"},
+		{"autolink", "<https://x.test>", `<p><a href="https://x.test">https://x.test</a></p>` + "\n"},
+		{"raw html is escaped", "<b>a</b> & b", "<p>&lt;b&gt;a&lt;/b&gt; &amp; b</p>\n"},
+		{"hard break", "a  \nb", "<p>a<br>\nb</p>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderMarkdown(tt.input); got != tt.want {
+				t.Errorf("RenderMarkdown(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownBlocks(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"heading", "# Hello world", `<h1 id="hello-world">Hello world</h1>` + "\n"},
+		{"duplicate headings", "## A\n## A", `<h2 id="a">A</h2>` + "\n" + `<h2 id="a-1">A</h2>` + "\n"},
+		{"setext heading", "Title\n=====", `<h1 id="title">Title</h1>` + "\n"},
+		{"fenced code", "```go\na < b\n```", `<pre><code class="language-go">a &lt; b` + "\n</code></pre>\n"},
+		{"blockquote", "> a\n> b", "<blockquote>\n<p>a\nb</p>\n</blockquote>\n"},
+		{"tight list", "- a\n- b", "<ul>\n<li>a</li>\n<li>b</li>\n</ul>\n"},
+		{"ordered list start", "3. a\n4. b", "<ol start=\"3\">\n<li>a</li>\n<li>b</li>\n</ol>\n"},
+		{"thematic break", "***", "<hr>\n"},
+		{"table", "| a | b |\n|:--|--:|\n| 1 | 2 |", "<table>\n<thead>\n<tr>\n<th align=\"left\">a</th>\n<th align=\"right\">b</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td align=\"left\">1</td>\n<td align=\"right\">2</td>\n</tr>\n</tbody>\n</table>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderMarkdown(tt.input); got != tt.want {
+				t.Errorf("RenderMarkdown(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// Pathological inputs must render in roughly linear time
+func TestRenderMarkdownLinearTime(t *testing.T) {
+	inputs := []string{"*a ", "_a ", "**a ", "~~a ", "[a ", "`a ", "![a ", "*a* ", ">"}
+	for _, unit := range inputs {
+		source := strings.Repeat(unit, 256*1024/len(unit))
+		start := time.Now()
+		RenderMarkdown(source)
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("rendering %q repeated to 256 KB took %v", unit, elapsed)
+		}
+	}
+}
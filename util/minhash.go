@@ -0,0 +1,126 @@
+package util
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+// MinHash parameters. Changing any of them invalidates stored signatures.
+const (
+	// ShingleSize is the number of consecutive words in a shingle
+	ShingleSize = 5
+	// MinHashSize is the number of hash functions in a signature
+	MinHashSize = 128
+	// LSHBands is the number of bands a signature is split into for
+	// locality-sensitive hashing. With 4 rows per band, texts become
+	// candidates of each other from roughly 40% similarity.
+	LSHBands = 32
+)
+
+// minHashSeed seeds the hash functions of the signature
+const minHashSeed = 0x6f63726f6c7573
+
+var minHashSeeds = func() []uint64 {
+	seeds := make([]uint64, MinHashSize)
+	state := uint64(minHashSeed)
+	for i := range seeds {
+		state += 0x9e3779b97f4a7c15
+		seeds[i] = mix64(state)
+	}
+	return seeds
+}()
+
+// mix64 is the splitmix64 finalizer, used to derive independent hash
+// functions from a single shingle hash
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// shingleHashes returns the hashes of the distinct word shingles of text.
+// Texts shorter than a shingle form a single shingle.
+func shingleHashes(text string) map[uint64]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return nil
+	}
+
+	hashes := map[uint64]bool{}
+	for i := 0; i == 0 || i+ShingleSize <= len(words); i++ {
+		end := i + ShingleSize
+		if end > len(words) {
+			end = len(words)
+		}
+		hash := fnv.New64a()
+		hash.Write([]byte(strings.Join(words[i:end], " ")))
+		hashes[hash.Sum64()] = true
+	}
+	return hashes
+}
+
+// MinHash computes the MinHash signature of the word shingles of text, or
+// nil for text without words. The share of positions at which two
+// signatures agree estimates the Jaccard similarity of the shingle sets.
+func MinHash(text string) []uint32 {
+	shingles := shingleHashes(text)
+	if len(shingles) == 0 {
+		return nil
+	}
+
+	signature := make([]uint32, MinHashSize)
+	for i := range signature {
+		signature[i] = ^uint32(0)
+	}
+	for shingle := range shingles {
+		for i, seed := range minHashSeeds {
+			if value := uint32(mix64(shingle^seed) >> 32); value < signature[i] {
+				signature[i] = value
+			}
+		}
+	}
+	return signature
+}
+
+// MinHashSimilarity estimates the similarity of two texts from their
+// signatures, between 0 and 1
+func MinHashSimilarity(a, b []uint32) float64 {
+	if len(a) != MinHashSize || len(b) != MinHashSize {
+		return 0
+	}
+	equal := 0
+	for i := range a {
+		if a[i] == b[i] {
+			equal++
+		}
+	}
+	return float64(equal) / MinHashSize
+}
+
+// LSHBuckets hashes each band of a signature into a bucket key. Texts that
+// share a bucket for the same band are candidates for being similar.
+func LSHBuckets(signature []uint32) []int64 {
+	if len(signature) != MinHashSize {
+		return nil
+	}
+
+	rows := MinHashSize / LSHBands
+	buckets := make([]int64, LSHBands)
+	buffer := make([]byte, 4)
+	for band := range buckets {
+		hash := fnv.New64a()
+		for _, value := range signature[band*rows : (band+1)*rows] {
+			binary.LittleEndian.PutUint32(buffer, value)
+			hash.Write(buffer)
+		}
+		buckets[band] = int64(hash.Sum64())
+	}
+	return buckets
+}
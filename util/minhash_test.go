@@ -0,0 +1,78 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// sampleText returns a text of distinct words, so that every shingle is
+// different
+func sampleText(prefix string, words int) string {
+	parts := make([]string, words)
+	for i := range parts {
+		parts[i] = fmt.Sprintf("%s%d", prefix, i)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestMinHashSimilarity(t *testing.T) {
+	text := sampleText("word", 200)
+
+	tests := []struct {
+		name     string
+		a, b     string
+		min, max float64
+	}{
+		{"identical", text, text, 1, 1},
+		{"case and punctuation", text, strings.ToUpper(strings.ReplaceAll(text, " ", ", ")), 1, 1},
+		{"disjoint", text, sampleText("other", 200), 0, 0.05},
+		// Replacing the last quarter keeps 146 of 196 shingles of each
+		// text, a Jaccard similarity of about 0.6
+		{"overlapping", text, sampleText("word", 150) + " " + sampleText("other", 50), 0.45, 0.75},
+		{"short texts", "hello there", "hello there", 1, 1},
+	}
+	for _, tt := range tests {
+		similarity := MinHashSimilarity(MinHash(tt.a), MinHash(tt.b))
+		if similarity < tt.min || similarity > tt.max {
+			t.Errorf("%s: similarity %.2f, want between %.2f and %.2f", tt.name, similarity, tt.min, tt.max)
+		}
+	}
+}
+
+func TestMinHashEmpty(t *testing.T) {
+	if signature := MinHash(" ... "); signature != nil {
+		t.Errorf("MinHash of a text without words = %v, want nil", signature)
+	}
+	if similarity := MinHashSimilarity(nil, MinHash("some words")); similarity != 0 {
+		t.Errorf("similarity with a missing signature = %v, want 0", similarity)
+	}
+}
+
+func TestLSHBuckets(t *testing.T) {
+	text := sampleText("word", 100)
+	a, b := LSHBuckets(MinHash(text)), LSHBuckets(MinHash(text))
+	if len(a) != LSHBands {
+		t.Fatalf("LSHBuckets returned %d buckets, want %d", len(a), LSHBands)
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("identical texts fall in different buckets for band %d", i)
+		}
+	}
+
+	other := LSHBuckets(MinHash(sampleText("other", 100)))
+	shared := 0
+	for i := range a {
+		if a[i] == other[i] {
+			shared++
+		}
+	}
+	if shared > 0 {
+		t.Errorf("disjoint texts share %d buckets", shared)
+	}
+
+	if buckets := LSHBuckets(nil); buckets != nil {
+		t.Errorf("LSHBuckets(nil) = %v, want nil", buckets)
+	}
+}
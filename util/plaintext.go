@@ -0,0 +1,55 @@
+package util
+
+import (
+	"html"
+	"strings"
+)
+
+// RenderPlaintext converts plain text to HTML. Blank lines separate
+// paragraphs and single newlines become line breaks.
+func RenderPlaintext(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var builder strings.Builder
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		paragraph = strings.Trim(paragraph, "\n")
+		if strings.TrimSpace(paragraph) == "" {
+			continue
+		}
+		lines := strings.Split(paragraph, "\n")
+		for i, line := range lines {
+			lines[i] = html.EscapeString(line)
+		}
+		builder.WriteString("<p>" + strings.Join(lines, "<br>\n") + "</p>\n")
+	}
+	return builder.String()
+}
+
+// Excerpt collapses the whitespace in text and shortens it to at most
+// maxRunes runes, cutting at a word boundary and marking the cut with an
+// ellipsis
+func Excerpt(text string, maxRunes int) string {
+	words := strings.Fields(text)
+
+	var builder strings.Builder
+	length := 0
+	for i, word := range words {
+		wordLength := len([]rune(word))
+		if i > 0 {
+			wordLength++
+		}
+		if length+wordLength > maxRunes {
+			if length == 0 {
+				// A single overlong word is cut mid-word
+				return string([]rune(word)[:maxRunes]) + "…"
+			}
+			return builder.String() + "…"
+		}
+		if i > 0 {
+			builder.WriteByte(' ')
+		}
+		builder.WriteString(word)
+		length += wordLength
+	}
+	return builder.String()
+}
@@ -0,0 +1,282 @@
+package util
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// allowedTags maps each permitted element to the attributes it may keep
+var allowedTags = map[string][]string{
+	"p": nil, "br": nil, "hr": nil,
+	"h1": {"id"}, "h2": {"id"}, "h3": {"id"}, "h4": {"id"}, "h5": {"id"}, "h6": {"id"},
+	"strong": nil, "b": nil, "em": nil, "i": nil, "del": nil, "s": nil,
+	"sup": nil, "sub": nil, "span": nil,
+	"code": {"class"}, "pre": nil, "blockquote": nil,
+	"ul": nil, "ol": {"start"}, "li": nil,
+	"a":      {"href", "title"},
+	"img":    {"src", "alt", "title"},
+	"figure": nil, "figcaption": nil,
+	"table": nil, "thead": nil, "tbody": nil, "tr": nil,
+	"th": {"align"}, "td": {"align"},
+}
+
+// droppedContentTags are removed together with everything inside them
+var droppedContentTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true,
+	"embed": true, "noscript": true, "textarea": true, "template": true,
+	"title": true, "head": true,
+}
+
+var voidTags = map[string]bool{"br": true, "hr": true, "img": true}
+
+var (
+	codeClassPattern = regexp.MustCompile(`^language-[a-z0-9+#_-]+$`)
+	alignPattern     = regexp.MustCompile(`^(left|center|right)$`)
+	numberPattern    = regexp.MustCompile(`^[0-9]{1,9}$`)
+	idPattern        = regexp.MustCompile(`^[\pL\pN_-]+$`)
+)
+
+// SanitizeHTML filters HTML through an allowlist of elements and attributes.
+// Anything not on the list is dropped, links and images may only use safe URL
+// schemes, and the output always has balanced tags.
+func SanitizeHTML(input string) string {
+	var builder strings.Builder
+	var open []string
+	skipDepth := 0
+
+	tokenizer := html.NewTokenizer(strings.NewReader(input))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			// io.EOF or malformed input; either way there is nothing more to read
+			break
+		}
+
+		token := tokenizer.Token()
+		switch tokenType {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if droppedContentTags[token.Data] {
+				if tokenType == html.StartTagToken {
+					skipDepth++
+				}
+				continue
+			}
+			if skipDepth > 0 {
+				continue
+			}
+			allowedAttrs, ok := allowedTags[token.Data]
+			if !ok {
+				continue
+			}
+			writeStartTag(&builder, token.Data, filterAttributes(token.Data, token.Attr, allowedAttrs))
+			if !voidTags[token.Data] && tokenType == html.StartTagToken {
+				open = append(open, token.Data)
+			}
+
+		case html.EndTagToken:
+			if droppedContentTags[token.Data] {
+				if skipDepth > 0 {
+					skipDepth--
+				}
+				continue
+			}
+			if skipDepth > 0 {
+				continue
+			}
+			// Close everything up to the matching open tag, ignoring stray end tags
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == token.Data {
+					for j := len(open) - 1; j >= i; j-- {
+						builder.WriteString("</" + open[j] + ">")
+					}
+					open = open[:i]
+					break
+				}
+			}
+
+		case html.TextToken:
+			if skipDepth == 0 {
+				builder.WriteString(html.EscapeString(token.Data))
+			}
+		}
+	}
+
+	for i := len(open) - 1; i >= 0; i-- {
+		builder.WriteString("</" + open[i] + ">")
+	}
+	return builder.String()
+}
+
+func filterAttributes(tag string, attrs []html.Attribute, allowed []string) []html.Attribute {
+	var kept []html.Attribute
+	for _, attr := range attrs {
+		if !contains(allowed, attr.Key) {
+			continue
+		}
+		value := strings.TrimSpace(attr.Val)
+		valid := true
+		switch attr.Key {
+		case "href":
+			valid = isSafeURL(value, true)
+		case "src":
+			valid = isSafeURL(value, false)
+		case "class":
+			valid = codeClassPattern.MatchString(value)
+		case "align":
+			valid = alignPattern.MatchString(value)
+		case "start":
+			valid = numberPattern.MatchString(value)
+		case "id":
+			valid = idPattern.MatchString(value)
+		}
+		if valid {
+			kept = append(kept, html.Attribute{Key: attr.Key, Val: value})
+		}
+	}
+
+	if tag == "a" {
+		kept = append(kept, html.Attribute{Key: "rel", Val: "nofollow noopener noreferrer"})
+	}
+	return kept
+}
+
+// isSafeURL allows relative URLs and http(s) URLs, plus mailto for links
+func isSafeURL(raw string, allowMailto bool) bool {
+	if raw == "" {
+		return false
+	}
+	for _, r := range raw {
+		if r < 0x20 || r == 0x7f {
+			return false
+		}
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "":
+		return true
+	case "http", "https":
+		return true
+	case "mailto":
+		return allowMailto
+	}
+	return false
+}
+
+func writeStartTag(builder *strings.Builder, tag string, attrs []html.Attribute) {
+	builder.WriteString("<" + tag)
+	for _, attr := range attrs {
+		builder.WriteString(" " + attr.Key + `="` + html.EscapeString(attr.Val) + `"`)
+	}
+	builder.WriteString(">")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// HTMLToText extracts the text content of an HTML fragment, dropping markup
+// and the content of scripts and styles
+func HTMLToText(input string) string {
+	var builder strings.Builder
+	skipDepth := 0
+
+	tokenizer := html.NewTokenizer(strings.NewReader(input))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			break
+		}
+
+		token := tokenizer.Token()
+		switch tokenType {
+		case html.StartTagToken:
+			if droppedContentTags[token.Data] {
+				skipDepth++
+			}
+		case html.EndTagToken:
+			if droppedContentTags[token.Data] && skipDepth > 0 {
+				skipDepth--
+			}
+		case html.TextToken:
+			if skipDepth == 0 {
+				builder.WriteString(token.Data)
+			}
+		}
+	}
+	return builder.String()
+}
+
+// TextBlock is the text of one block-level element of an HTML fragment
+type TextBlock struct {
+	Text    string
+	Heading bool
+}
+
+// textBlockTags are the elements whose text forms a separate block
+var textBlockTags = map[string]bool{
+	"p": true, "li": true, "blockquote": true, "td": true, "th": true,
+	"figcaption": true, "dd": true, "dt": true, "div": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// HTMLTextBlocks splits the text of an HTML fragment into its paragraphs,
+// headings and other blocks, with whitespace collapsed. Code blocks are left
+// out because they are not prose.
+func HTMLTextBlocks(input string) []TextBlock {
+	var blocks []TextBlock
+	var builder strings.Builder
+	skipDepth := 0
+	heading := false
+
+	flush := func() {
+		if text := strings.Join(strings.Fields(builder.String()), " "); text != "" {
+			blocks = append(blocks, TextBlock{Text: text, Heading: heading})
+		}
+		builder.Reset()
+	}
+
+	tokenizer := html.NewTokenizer(strings.NewReader(input))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			break
+		}
+
+		token := tokenizer.Token()
+		switch tokenType {
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			if droppedContentTags[token.Data] || token.Data == "pre" {
+				if tokenType == html.StartTagToken {
+					skipDepth++
+				} else if tokenType == html.EndTagToken && skipDepth > 0 {
+					skipDepth--
+				}
+				continue
+			}
+			if textBlockTags[token.Data] {
+				flush()
+				heading = tokenType == html.StartTagToken && len(token.Data) == 2 && token.Data[0] == 'h'
+			} else if token.Data == "br" {
+				builder.WriteByte(' ')
+			}
+		case html.TextToken:
+			if skipDepth == 0 {
+				builder.WriteString(token.Data)
+			}
+		}
+	}
+	flush()
+	return blocks
+}
@@ -0,0 +1,72 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"allowed markup is kept", "<p><strong>a</strong> <em>b</em></p>", "<p><strong>a</strong> <em>b</em></p>"},
+		{"script is dropped with its content", "a<script>alert(1)</script>b", "ab"},
+		{"nested dropped content", "<style><script>x</script>y</style>z", "z"},
+		{"unknown tags are unwrapped", "<div><font>a</font></div>", "a"},
+		{"event handlers are dropped", `<p onclick="x()">a</p>`, "<p>a</p>"},
+		{"javascript links are dropped", `<a href="javascript:alert(1)">a</a>`, `<a rel="nofollow noopener noreferrer">a</a>`},
+		{"mixed case scheme", `<a href="JaVaScRiPt:alert(1)">a</a>`, `<a rel="nofollow noopener noreferrer">a</a>`},
+		{"control characters in url", "<a href=\"java\tscript:x\">a</a>", `<a rel="nofollow noopener noreferrer">a</a>`},
+		{"entity encoded scheme", `<a href="&#106;avascript:x">a</a>`, `<a rel="nofollow noopener noreferrer">a</a>`},
+		{"http links get rel", `<a href="https://x.test" title="t">a</a>`, `<a href="https://x.test" title="t" rel="nofollow noopener noreferrer">a</a>`},
+		{"mailto links", `<a href="mailto:a@x.test">a</a>`, `<a href="mailto:a@x.test" rel="nofollow noopener noreferrer">a</a>`},
+		{"mailto images are dropped", `<img src="mailto:a@x.test" alt="a">`, `<img alt="a">`},
+		{"data images are dropped", `<img src="data:image/svg+xml,<svg onload=x>">`, "<img>"},
+		{"relative urls", `<img src="/i.png">`, `<img src="/i.png">`},
+		{"invalid code class", `<code class="x onmouseover">a</code>`, "<code>a</code>"},
+		{"language class", `<code class="language-go">a</code>`, `<code class="language-go">a</code>`},
+		{"attribute values are escaped", `<img alt="&quot;><script>">`, `<img alt="&#34;&gt;&lt;script&gt;">`},
+		{"text is escaped", "a &lt;b&gt; c", "a &lt;b&gt; c"},
+		{"unclosed tags are closed", "<ul><li><em>a", "<ul><li><em>a</em></li></ul>"},
+		{"stray end tags are ignored", "a</p></em>b", "ab"},
+		{"misnested tags", "<strong><em>a</strong>b</em>", "<strong><em>a</em></strong>b"},
+		{"ordered list start", `<ol start="3x"><li>a</li></ol>`, "<ol><li>a</li></ol>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeHTML(tt.input); got != tt.want {
+				t.Errorf("SanitizeHTML(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLToText(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"<p>a <em>b</em></p>", "a b"},
+		{"a<script>x</script><style>y</style>b", "ab"},
+		{"a &amp; b", "a & b"},
+	}
+	for _, tt := range tests {
+		if got := HTMLToText(tt.input); got != tt.want {
+			t.Errorf("HTMLToText(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestHTMLTextBlocks(t *testing.T) {
+	input := "<h2>Title</h2><p>One <b>two</b><br>three</p><pre><code>skip</code></pre><ul><li>item</li></ul>"
+	want := []TextBlock{
+		{Text: "Title", Heading: true},
+		{Text: "One two three"},
+		{Text: "item"},
+	}
+	if got := HTMLTextBlocks(input); !reflect.DeepEqual(got, want) {
+		t.Errorf("HTMLTextBlocks(%q) = %+v, want %+v", input, got, want)
+	}
+}
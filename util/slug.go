@@ -0,0 +1,58 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxSlugLength is the maximum number of runes in a generated slug
+const maxSlugLength = 80
+
+// Slugify turns arbitrary text into a URL-friendly slug. Letters, digits and
+// the marks combined with them are kept from any script, accents are
+// stripped from Latin letters, and everything else collapses into single
+// hyphens. The slug is returned in NFC form.
+func Slugify(text string) string {
+	var builder strings.Builder
+	pendingHyphen := false
+	length := 0
+	// The letter or digit the current word last ended with, 0 between words
+	var base rune
+
+	for _, r := range norm.NFKD.String(text) {
+		if unicode.Is(unicode.M, r) {
+			// Marks belong to the preceding letter. Accents on Latin letters
+			// are dropped; in other scripts marks are part of the spelling,
+			// such as Devanagari vowel signs or Japanese dakuten.
+			if base != 0 && !(unicode.Is(unicode.Latin, base) && unicode.Is(unicode.Mn, r)) {
+				builder.WriteRune(r)
+			}
+			continue
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if length >= maxSlugLength {
+				break
+			}
+			if pendingHyphen && length > 0 {
+				builder.WriteRune('-')
+				length++
+			}
+			builder.WriteRune(unicode.ToLower(r))
+			length++
+			pendingHyphen = false
+			base = r
+			continue
+		}
+		pendingHyphen = true
+		base = 0
+	}
+
+	return norm.NFC.String(strings.Trim(builder.String(), "-"))
+}
+
+// IsValidSlug reports whether slug is already in canonical slug form
+func IsValidSlug(slug string) bool {
+	return slug != "" && Slugify(slug) == slug
+}
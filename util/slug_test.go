@@ -0,0 +1,51 @@
+package util
+
+import "testing"
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Hello, World!", "hello-world"},
+		{"  --Go  is   fun--  ", "go-is-fun"},
+		{"Crème brûlée à la carte", "creme-brulee-a-la-carte"},
+		{"Tiếng Việt", "tieng-viet"},
+		{"Ｆｕｌｌｗｉｄｔｈ １２３", "fullwidth-123"},
+		{"हिन्दी भाषा", "हिन्दी-भाषा"},
+		{"デザイン", "デザイン"},
+		{"한국어 문법", "한국어-문법"},
+		{"Ελληνικά", "ελληνικά"},
+		{"Русский язык", "русский-язык"},
+		{"中文 标题", "中文-标题"},
+		{"́leading mark", "leading-mark"},
+		{"!!!", ""},
+	}
+	for _, tt := range tests {
+		if got := Slugify(tt.input); got != tt.want {
+			t.Errorf("Slugify(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestIsValidSlug(t *testing.T) {
+	tests := []struct {
+		slug string
+		want bool
+	}{
+		{"hello-world", true},
+		{"한국어", true},
+		{"हिन्दी-भाषा", true},
+		{"デザイン", true},
+		{"Hello", false},
+		{"hello--world", false},
+		{"-hello", false},
+		{"crème", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsValidSlug(tt.slug); got != tt.want {
+			t.Errorf("IsValidSlug(%q) = %v, want %v", tt.slug, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package util
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// wordsPerMinute is the reading speed used for reading time estimates
+const wordsPerMinute = 200
+
+// maxSummaryCandidates bounds the number of sentences ranked for a summary,
+// since ranking compares every pair of sentences
+const maxSummaryCandidates = 200
+
+// TextRank parameters
+const (
+	rankDamping    = 0.85
+	rankTolerance  = 1e-4
+	rankIterations = 100
+)
+
+// stopWords are common English words ignored when comparing sentences and
+// extracting keywords
+var stopWords = map[string]bool{
+	"a": true, "about": true, "after": true, "all": true, "also": true, "an": true, "and": true,
+	"any": true, "are": true, "as": true, "at": true, "be": true, "been": true, "but": true,
+	"by": true, "can": true, "could": true, "do": true, "does": true, "for": true, "from": true,
+	"had": true, "has": true, "have": true, "he": true, "her": true, "his": true, "how": true,
+	"i": true, "if": true, "in": true, "into": true, "is": true, "it": true, "its": true,
+	"just": true, "more": true, "most": true, "my": true, "no": true, "not": true, "of": true,
+	"on": true, "one": true, "only": true, "or": true, "other": true, "our": true, "out": true,
+	"over": true, "she": true, "so": true, "some": true, "such": true, "than": true, "that": true,
+	"the": true, "their": true, "them": true, "then": true, "there": true, "these": true,
+	"they": true, "this": true, "to": true, "up": true, "us": true, "was": true, "we": true,
+	"were": true, "what": true, "when": true, "which": true, "who": true, "will": true,
+	"with": true, "would": true, "you": true, "your": true,
+}
+
+// sentenceAbbreviations end in a period without ending a sentence
+var sentenceAbbreviations = map[string]bool{
+	"mr.": true, "mrs.": true, "ms.": true, "dr.": true, "prof.": true, "st.": true,
+	"vs.": true, "etc.": true, "e.g.": true, "i.e.": true, "no.": true, "fig.": true,
+}
+
+// WordCount counts the whitespace-separated words in text
+func WordCount(text string) int {
+	return len(strings.Fields(text))
+}
+
+// ReadingTime estimates the minutes needed to read the given number of
+// words, rounding up so that any text takes at least a minute
+func ReadingTime(words int) int {
+	return (words + wordsPerMinute - 1) / wordsPerMinute
+}
+
+// Summarize picks the most central sentences of a text with TextRank:
+// sentences are ranked like web pages, with links weighted by how many
+// words two sentences share. The chosen sentences are returned in their
+// original order. Texts with no more than the requested number of sentences
+// are not summarized and give an empty string.
+func Summarize(paragraphs []string, sentences int) string {
+	var candidates []string
+	for _, paragraph := range paragraphs {
+		candidates = append(candidates, SplitSentences(paragraph)...)
+	}
+	if len(candidates) > maxSummaryCandidates {
+		candidates = candidates[:maxSummaryCandidates]
+	}
+	if sentences <= 0 || len(candidates) <= sentences {
+		return ""
+	}
+
+	words := make([]map[string]bool, len(candidates))
+	for i, sentence := range candidates {
+		words[i] = summaryWords(sentence)
+	}
+
+	// Edge weights between every pair of sentences
+	n := len(candidates)
+	weights := make([][]float64, n)
+	totals := make([]float64, n)
+	for i := range weights {
+		weights[i] = make([]float64, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			weight := sentenceSimilarity(words[i], words[j])
+			weights[i][j], weights[j][i] = weight, weight
+			totals[i] += weight
+			totals[j] += weight
+		}
+	}
+
+	scores := make([]float64, n)
+	for i := range scores {
+		scores[i] = 1
+	}
+	for iteration := 0; iteration < rankIterations; iteration++ {
+		next := make([]float64, n)
+		change := 0.0
+		for i := 0; i < n; i++ {
+			rank := 0.0
+			for j := 0; j < n; j++ {
+				if weights[j][i] > 0 {
+					rank += weights[j][i] / totals[j] * scores[j]
+				}
+			}
+			next[i] = 1 - rankDamping + rankDamping*rank
+			change = math.Max(change, math.Abs(next[i]-scores[i]))
+		}
+		scores = next
+		if change < rankTolerance {
+			break
+		}
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]] > scores[order[b]]
+	})
+	chosen := order[:sentences]
+	sort.Ints(chosen)
+
+	summary := make([]string, len(chosen))
+	for i, index := range chosen {
+		summary[i] = candidates[index]
+	}
+	return strings.Join(summary, " ")
+}
+
+// SplitSentences splits a paragraph into sentences at ., ! and ? followed by
+// whitespace, except after common abbreviations and initials or when the
+// next word starts in lowercase
+func SplitSentences(text string) []string {
+	words := strings.Fields(text)
+	var sentences []string
+	start := 0
+	for i, word := range words {
+		if i == len(words)-1 || !endsSentence(word, words[i+1]) {
+			continue
+		}
+		sentences = append(sentences, strings.Join(words[start:i+1], " "))
+		start = i + 1
+	}
+	if start < len(words) {
+		sentences = append(sentences, strings.Join(words[start:], " "))
+	}
+	return sentences
+}
+
+// endsSentence reports whether word ends a sentence given the word after it
+func endsSentence(word, next string) bool {
+	trimmed := strings.TrimRight(word, `"')]’”`)
+	if trimmed == "" || !strings.ContainsAny(trimmed[len(trimmed)-1:], ".!?") {
+		return false
+	}
+	if strings.HasSuffix(trimmed, ".") {
+		if sentenceAbbreviations[strings.ToLower(trimmed)] {
+			return false
+		}
+		// Initials such as "J."
+		if runes := []rune(trimmed); len(runes) == 2 && unicode.IsUpper(runes[0]) {
+			return false
+		}
+	}
+	first := []rune(strings.TrimLeft(next, `"'(‘“`))
+	return len(first) == 0 || !unicode.IsLower(first[0])
+}
+
+// summaryWords returns the distinct content words of a sentence
+func summaryWords(sentence string) map[string]bool {
+	words := map[string]bool{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(sentence), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !stopWords[word] {
+			words[word] = true
+		}
+	}
+	return words
+}
+
+// sentenceSimilarity is the TextRank similarity of two sentences: the words
+// they share, normalized by their lengths so long sentences are not favoured
+func sentenceSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+	if shared == 0 {
+		return 0
+	}
+	return float64(shared) / (math.Log(float64(len(a)+1)) + math.Log(float64(len(b)+1)))
+}
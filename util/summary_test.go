@@ -0,0 +1,81 @@
+package util
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitSentences(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"One. Two! Three?", []string{"One.", "Two!", "Three?"}},
+		{"Ask Dr. Smith. He knows.", []string{"Ask Dr. Smith.", "He knows."}},
+		{"Written by J. R. Tolkien. Read it.", []string{"Written by J. R. Tolkien.", "Read it."}},
+		{"Fruit, e.g. apples, is good. Eat it.", []string{"Fruit, e.g. apples, is good.", "Eat it."}},
+		{"Version 2.0 is out. the rest follows.", []string{"Version 2.0 is out. the rest follows."}},
+		{`He said "stop." Then he left.`, []string{`He said "stop."`, "Then he left."}},
+		{"No final stop", []string{"No final stop"}},
+		{"  spaced   out.  Words  ", []string{"spaced out.", "Words"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := SplitSentences(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitSentences(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	paragraphs := []string{
+		"Go is a programming language designed at Google. Go programs compile quickly to native code.",
+		"The weather was nice yesterday.",
+		"Many programming teams use Go for network services. The Go compiler and programming tools are fast.",
+	}
+
+	summary := Summarize(paragraphs, 2)
+	if summary == "" {
+		t.Fatal("Summarize returned nothing")
+	}
+	if strings.Contains(summary, "weather") {
+		t.Errorf("Summarize picked the off-topic sentence: %q", summary)
+	}
+	if got := len(SplitSentences(summary)); got != 2 {
+		t.Errorf("Summarize returned %d sentences, want 2: %q", got, summary)
+	}
+	// Chosen sentences keep their original order
+	first := strings.Index(summary, "Go is a programming language")
+	last := strings.Index(summary, "The Go compiler")
+	if first >= 0 && last >= 0 && first > last {
+		t.Errorf("Summarize reordered sentences: %q", summary)
+	}
+
+	if got := Summarize(paragraphs, 5); got != "" {
+		t.Errorf("Summarize of a text with only 5 sentences = %q, want empty", got)
+	}
+	if got := Summarize(paragraphs, 0); got != "" {
+		t.Errorf("Summarize with no sentences requested = %q, want empty", got)
+	}
+	if got := Summarize(nil, 3); got != "" {
+		t.Errorf("Summarize(nil) = %q, want empty", got)
+	}
+}
+
+func TestReadingTime(t *testing.T) {
+	tests := []struct {
+		words int
+		want  int
+	}{
+		{0, 0},
+		{1, 1},
+		{wordsPerMinute, 1},
+		{wordsPerMinute + 1, 2},
+	}
+	for _, tt := range tests {
+		if got := ReadingTime(tt.words); got != tt.want {
+			t.Errorf("ReadingTime(%d) = %d, want %d", tt.words, got, tt.want)
+		}
+	}
+}